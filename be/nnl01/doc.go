@@ -0,0 +1,34 @@
+// Package nnl01 implements public-key broadcast encryption with the
+// subset-difference revocation method from the [paper]:
+//
+//	@inproceedings{01-crypto-revocation_stateless_receivers,
+//	    title = {Revocation and Tracing Schemes for Stateless Receivers},
+//	    author = {Naor, Dalit and Naor, Moni and Lotspiech, Jeffrey B.},
+//	    booktitle = {International Cryptology Conference (CRYPTO)},
+//	    year = {2001},
+//	}
+//
+// The users are the leaves of a binary tree of depth d.  To encrypt to all
+// users except a revoked set R, [Encrypt] computes a cover of the other users
+// by subsets S_{i,j}: the users below node i but not below its descendant j.
+// The cover has at most 2|R| - 1 subsets, however many users there are.  The
+// message is encrypted under a random session key, and the header holds one
+// encapsulation of the session key per subset.
+//
+// # Changes from Paper
+// The paper derives the subset keys with a pseudorandom generator, so only
+// the center can encrypt.  This package follows the public-key variant of
+// Dodis and Fazio (DRM 2002), which realizes the subset keys with a HIBE
+// (here, [bbg05]): S_{i,j} is the HIBE identity (i, b_1, ..., b_k), where
+// b_1 ... b_k is the path from i to j.  A user u gets, for each ancestor i
+// and each node w that hangs off the path from i to u, the HIBE key for
+// (i, path from i to w).  From it, u derives the key for any S_{i,j} with j
+// below w, which are exactly the subsets that contain u; a revoked user
+// below j holds no key for a prefix of that identity.  Each user stores
+// O(d^2) HIBE keys, anyone can encrypt, and since [bbg05] ciphertexts have
+// constant size, each subset adds a constant amount to the header: two node
+// indices and one [bbg05.Ciphertext] (see [Ciphertext.HeaderSize]).  The set of all users, for R empty, is a special
+// subset with its own HIBE identity.
+//
+// [paper]: https://www.wisdom.weizmann.ac.il/~naor/PAPERS/2nl.pdf
+package nnl01
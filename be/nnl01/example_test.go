@@ -0,0 +1,41 @@
+package nnl01_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/etclab/ncircl/be/nnl01"
+)
+
+// Example shows how to encrypt a message to every user but three.
+func Example() {
+	pp, msk, err := nnl01.Setup(6)
+	if err != nil {
+		log.Fatalf("failed to set up: %v", err)
+	}
+
+	aliceKey, err := nnl01.KeyGen(pp, msk, 10)
+	if err != nil {
+		log.Fatalf("failed to generate key: %v", err)
+	}
+	malloryKey, err := nnl01.KeyGen(pp, msk, 42)
+	if err != nil {
+		log.Fatalf("failed to generate key: %v", err)
+	}
+
+	ct, err := nnl01.Encrypt(pp, []int{7, 42, 43}, []byte("The quick brown fox jumps over the lazy dog."))
+	if err != nil {
+		log.Fatalf("failed to encrypt: %v", err)
+	}
+	fmt.Printf("%d of %d users, %d subsets\n", pp.NumUsers()-3, pp.NumUsers(), len(ct.Subsets))
+
+	got, err := nnl01.Decrypt(pp, aliceKey, ct)
+	fmt.Println(string(got), err)
+
+	_, err = nnl01.Decrypt(pp, malloryKey, ct)
+	fmt.Println(err)
+	// Output:
+	// 61 of 64 users, 2 subsets
+	// The quick brown fox jumps over the lazy dog. <nil>
+	// nnl01: user is revoked
+}
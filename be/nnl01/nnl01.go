@@ -0,0 +1,344 @@
+package nnl01
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+
+	"github.com/etclab/mu"
+	"github.com/etclab/ncircl/hibe/bbg05"
+	"github.com/etclab/ncircl/util/aesx"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// MaxDepth is the maximum depth of the tree of users.
+const MaxDepth = 30
+
+var (
+	ErrInvalidDepth   = errors.New("nnl01: invalid tree depth")
+	ErrInvalidUser    = errors.New("nnl01: invalid user")
+	ErrRevoked        = errors.New("nnl01: user is revoked")
+	ErrInvalidPayload = errors.New("nnl01: invalid payload")
+)
+
+var (
+	nodeLabel = []byte("nnl01-node")
+	allLabel  = []byte("nnl01-all")
+)
+
+// Nodes are numbered as in a binary heap: the root is 1, and the children of
+// node x are 2x and 2x+1.  User u is the leaf 2^d + u.
+
+func depthOf(x int) int {
+	d := -1
+	for ; x > 0; x >>= 1 {
+		d++
+	}
+	return d
+}
+
+// isBelow reports whether x is in the subtree of (or equal to) node a.
+func isBelow(x, a int) bool {
+	k := depthOf(x) - depthOf(a)
+	return k >= 0 && x>>k == a
+}
+
+type PublicParams struct {
+	// Depth is the depth of the tree of users; there are 2^Depth users.
+	Depth int
+	HIBE  *bbg05.PublicParams
+}
+
+func (pp *PublicParams) NumUsers() int {
+	return 1 << pp.Depth
+}
+
+func (pp *PublicParams) leaf(user int) int {
+	return pp.NumUsers() + user
+}
+
+type MasterKey struct {
+	HIBE *bbg05.MasterKey
+}
+
+func Setup(depth int) (*PublicParams, *MasterKey, error) {
+	if depth < 1 || depth > MaxDepth {
+		return nil, nil, ErrInvalidDepth
+	}
+	hibePP, hibeMSK := bbg05.Setup(depth + 1)
+	return &PublicParams{Depth: depth, HIBE: hibePP}, &MasterKey{HIBE: hibeMSK}, nil
+}
+
+// Subset is S_{I,J}, the users below node I but not below node J.  The set
+// of all users is the subset with I = 1 and J = 0.
+type Subset struct {
+	I int
+	J int
+}
+
+func (s Subset) isAll() bool {
+	return s.J == 0
+}
+
+// valid reports whether J is strictly below I, and within the tree.
+func (s Subset) valid(pp *PublicParams) bool {
+	if s.isAll() {
+		return s.I == 1
+	}
+	return s.I >= 1 && s.J != s.I && isBelow(s.J, s.I) && depthOf(s.J) <= pp.Depth
+}
+
+// Contains reports whether user is in the subset.
+func (s Subset) Contains(pp *PublicParams, user int) bool {
+	leaf := pp.leaf(user)
+	if s.isAll() {
+		return true
+	}
+	return isBelow(leaf, s.I) && !isBelow(leaf, s.J)
+}
+
+// hibeId returns the HIBE identity (i, path from i to x), for x below i.
+func hibeId(pp *PublicParams, i, x int) *bbg05.Id {
+	k := depthOf(x) - depthOf(i)
+	components := make([][]byte, 1+k)
+	components[0] = binary.BigEndian.AppendUint32(slices.Clone(nodeLabel), uint32(i))
+	for t := 1; t <= k; t++ {
+		bit := (x >> (k - t)) & 1
+		components[t] = []byte{'0' + byte(bit)}
+	}
+	id, err := bbg05.NewId(pp.HIBE, components)
+	if err != nil {
+		mu.Panicf("bbg05.NewId failed: %v", err)
+	}
+	return id
+}
+
+func allId(pp *PublicParams) *bbg05.Id {
+	id, err := bbg05.NewId(pp.HIBE, [][]byte{allLabel})
+	if err != nil {
+		mu.Panicf("bbg05.NewId failed: %v", err)
+	}
+	return id
+}
+
+func (s Subset) hibeId(pp *PublicParams) *bbg05.Id {
+	if s.isAll() {
+		return allId(pp)
+	}
+	return hibeId(pp, s.I, s.J)
+}
+
+// subsetKey is the key for the HIBE identity (I, path from I to W).
+type subsetKey struct {
+	I  int
+	W  int
+	SK *bbg05.PrivateKey
+}
+
+// UserKey holds a user's HIBE keys.
+type UserKey struct {
+	User int
+	All  *bbg05.PrivateKey
+	Keys []*subsetKey
+}
+
+// KeyGen returns the keys for user, which is in [0, 2^d).
+func KeyGen(pp *PublicParams, msk *MasterKey, user int) (*UserKey, error) {
+	if user < 0 || user >= pp.NumUsers() {
+		return nil, ErrInvalidUser
+	}
+
+	uk := &UserKey{User: user}
+
+	var err error
+	uk.All, err = bbg05.KeyGen(pp.HIBE, msk.HIBE, allId(pp))
+	if err != nil {
+		mu.Panicf("bbg05.KeyGen failed: %v", err)
+	}
+
+	leaf := pp.leaf(user)
+	for i := leaf >> 1; i >= 1; i >>= 1 {
+		// the siblings of the nodes on the path from i (exclusive) to leaf
+		for x := leaf; x > i; x >>= 1 {
+			w := x ^ 1
+			sk, err := bbg05.KeyGen(pp.HIBE, msk.HIBE, hibeId(pp, i, w))
+			if err != nil {
+				mu.Panicf("bbg05.KeyGen failed: %v", err)
+			}
+			uk.Keys = append(uk.Keys, &subsetKey{I: i, W: w, SK: sk})
+		}
+	}
+
+	return uk, nil
+}
+
+// key returns the HIBE key for the subset, derived from the user's keys, or
+// nil if the user is not in the subset.
+func (uk *UserKey) key(pp *PublicParams, s Subset) *bbg05.PrivateKey {
+	if s.isAll() {
+		return uk.All
+	}
+	if !s.Contains(pp, uk.User) {
+		return nil
+	}
+
+	for _, k := range uk.Keys {
+		if k.I != s.I || !isBelow(s.J, k.W) {
+			continue
+		}
+		sk := k.SK
+		for x := k.W; x != s.J; {
+			x = s.J >> (depthOf(s.J) - depthOf(x) - 1)
+			child, err := bbg05.KeyDer(pp.HIBE, sk, hibeId(pp, s.I, x))
+			if err != nil {
+				mu.Panicf("bbg05.KeyDer failed: %v", err)
+			}
+			sk = child
+		}
+		return sk
+	}
+
+	mu.Panicf("no key for subset (%d, %d)", s.I, s.J)
+	return nil
+}
+
+// Cover returns the subset-difference cover of all users except revoked.
+// The cover is empty if all users are revoked.
+func Cover(pp *PublicParams, revoked []int) ([]Subset, error) {
+	isRevoked := make(map[int]bool)
+	for _, u := range revoked {
+		if u < 0 || u >= pp.NumUsers() {
+			return nil, ErrInvalidUser
+		}
+		isRevoked[pp.leaf(u)] = true
+	}
+	if len(isRevoked) == 0 {
+		return []Subset{{I: 1, J: 0}}, nil
+	}
+
+	// hasRevoked[x] is true if a revoked leaf is below x
+	hasRevoked := make(map[int]bool)
+	for leaf := range isRevoked {
+		for x := leaf; x >= 1; x >>= 1 {
+			hasRevoked[x] = true
+		}
+	}
+
+	var cover []Subset
+	// hole returns the node below which are all the revoked leaves of x's
+	// subtree that are not yet covered, adding subsets to the cover at each
+	// node of the Steiner tree where two paths meet.
+	var hole func(x int) int
+	hole = func(x int) int {
+		if isRevoked[x] {
+			return x
+		}
+		l, r := 2*x, 2*x+1
+		switch {
+		case hasRevoked[l] && hasRevoked[r]:
+			if h := hole(l); h != l {
+				cover = append(cover, Subset{I: l, J: h})
+			}
+			if h := hole(r); h != r {
+				cover = append(cover, Subset{I: r, J: h})
+			}
+			return x
+		case hasRevoked[l]:
+			return hole(l)
+		default:
+			return hole(r)
+		}
+	}
+	if h := hole(1); h != 1 {
+		cover = append(cover, Subset{I: 1, J: h})
+	}
+
+	return cover, nil
+}
+
+// Ciphertext is a broadcast ciphertext.  Headers[k] encapsulates the session
+// key for Subsets[k].
+type Ciphertext struct {
+	Subsets []Subset
+	Headers []*bbg05.Ciphertext
+	Payload []byte
+}
+
+// The payload is bound to the whole header.
+func headerAD(ct *Ciphertext) []byte {
+	var m []byte
+	for k, s := range ct.Subsets {
+		m = binary.BigEndian.AppendUint32(m, uint32(s.I))
+		m = binary.BigEndian.AppendUint32(m, uint32(s.J))
+		m = append(m, blspairing.GtToBytes(ct.Headers[k].A)...)
+		m = append(m, ct.Headers[k].B.Bytes()...)
+		m = append(m, ct.Headers[k].C.Bytes()...)
+	}
+	return m
+}
+
+// HeaderSize returns the size in bytes of the header: for each subset, its
+// two node indices as uint32s and its bbg05 ciphertext as encoded by
+// [bbg05.Ciphertext.MarshalBinary].
+func (ct *Ciphertext) HeaderSize() int {
+	n := 0
+	for _, h := range ct.Headers {
+		b, err := h.MarshalBinary()
+		if err != nil {
+			mu.Panicf("bbg05.Ciphertext.MarshalBinary failed: %v", err)
+		}
+		n += 8 + len(b)
+	}
+	return n
+}
+
+// Encrypt encrypts msg to all users except revoked.
+func Encrypt(pp *PublicParams, revoked []int, msg []byte) (*Ciphertext, error) {
+	cover, err := Cover(pp, revoked)
+	if err != nil {
+		return nil, err
+	}
+
+	gt := blspairing.NewRandomGt()
+	ct := &Ciphertext{Subsets: cover}
+	for _, s := range cover {
+		h, err := bbg05.Encrypt(pp.HIBE, s.hibeId(pp), gt)
+		if err != nil {
+			return nil, err
+		}
+		ct.Headers = append(ct.Headers, h)
+	}
+
+	ct.Payload, err = aesx.EncryptGCM(blspairing.KdfGtToAes256(gt), msg, headerAD(ct))
+	if err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+// Decrypt returns [ErrRevoked] if no subset in the header contains the user.
+func Decrypt(pp *PublicParams, uk *UserKey, ct *Ciphertext) ([]byte, error) {
+	if len(ct.Subsets) != len(ct.Headers) {
+		return nil, ErrInvalidPayload
+	}
+
+	for k, s := range ct.Subsets {
+		if !s.valid(pp) {
+			return nil, ErrInvalidPayload
+		}
+
+		sk := uk.key(pp, s)
+		if sk == nil {
+			continue
+		}
+
+		gt := bbg05.Decrypt(pp.HIBE, sk, ct.Headers[k])
+		msg, err := aesx.DecryptGCM(blspairing.KdfGtToAes256(gt), ct.Payload, headerAD(ct))
+		if err != nil {
+			return nil, ErrInvalidPayload
+		}
+		return msg, nil
+	}
+
+	return nil, ErrRevoked
+}
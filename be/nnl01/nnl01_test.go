@@ -0,0 +1,213 @@
+package nnl01
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"testing"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+func TestCover(t *testing.T) {
+	pp, _, err := Setup(5)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	tests := [][]int{
+		nil,
+		{0},
+		{31},
+		{0, 31},
+		{3, 4, 5},
+		{0, 1, 2, 3, 4, 5, 6, 7},
+		{1, 6, 7, 12, 13, 20, 30},
+	}
+
+	for _, revoked := range tests {
+		t.Run(fmt.Sprint(revoked), func(t *testing.T) {
+			cover, err := Cover(pp, revoked)
+			if err != nil {
+				t.Fatalf("Cover failed: %v", err)
+			}
+			if len(revoked) > 0 && len(cover) > 2*len(revoked)-1 {
+				t.Fatalf("expected at most %d subsets, but got %d", 2*len(revoked)-1, len(cover))
+			}
+
+			isRevoked := make(map[int]bool)
+			for _, u := range revoked {
+				isRevoked[u] = true
+			}
+			for u := range pp.NumUsers() {
+				n := 0
+				for _, s := range cover {
+					if !s.valid(pp) {
+						t.Fatalf("invalid subset %v", s)
+					}
+					if s.Contains(pp, u) {
+						n++
+					}
+				}
+				if isRevoked[u] && n != 0 {
+					t.Fatalf("revoked user %d is covered", u)
+				}
+				if !isRevoked[u] && n != 1 {
+					t.Fatalf("user %d is covered %d times", u, n)
+				}
+			}
+		})
+	}
+
+	all := make([]int, pp.NumUsers())
+	for u := range all {
+		all[u] = u
+	}
+	if cover, err := Cover(pp, all); err != nil || len(cover) != 0 {
+		t.Fatalf("expected an empty cover, but got %v, %v", cover, err)
+	}
+
+	if _, err := Cover(pp, []int{pp.NumUsers()}); err != ErrInvalidUser {
+		t.Fatalf("expected ErrInvalidUser, but got %v", err)
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	pp, msk, err := Setup(3)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	keys := make([]*UserKey, pp.NumUsers())
+	for u := range keys {
+		keys[u], err = KeyGen(pp, msk, u)
+		if err != nil {
+			t.Fatalf("KeyGen failed: %v", err)
+		}
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	for _, revoked := range [][]int{nil, {2}, {0, 7}, {1, 2, 5}} {
+		ct, err := Encrypt(pp, revoked, msg)
+		if err != nil {
+			t.Fatalf("Encrypt failed: %v", err)
+		}
+
+		for u, uk := range keys {
+			got, err := Decrypt(pp, uk, ct)
+			if slices.Contains(revoked, u) {
+				if err != ErrRevoked {
+					t.Fatalf("revoked %v: expected ErrRevoked for user %d, but got %v", revoked, u, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("revoked %v: Decrypt failed for user %d: %v", revoked, u, err)
+			}
+			if !bytes.Equal(got, msg) {
+				t.Fatalf("revoked %v: user %d decrypted the wrong message", revoked, u)
+			}
+		}
+	}
+}
+
+// A revoked user holds no key from which to derive the key of a subset that
+// excludes it.
+func TestRevokedUserKeys(t *testing.T) {
+	pp, msk, err := Setup(3)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	uk, err := KeyGen(pp, msk, 5)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	cover, err := Cover(pp, []int{5})
+	if err != nil {
+		t.Fatalf("Cover failed: %v", err)
+	}
+	for _, s := range cover {
+		for _, k := range uk.Keys {
+			if k.I == s.I && isBelow(s.J, k.W) {
+				t.Fatalf("user key (%d, %d) derives subset %v", k.I, k.W, s)
+			}
+		}
+	}
+}
+
+func TestDecrypt_tampered(t *testing.T) {
+	pp, msk, err := Setup(3)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	uk, err := KeyGen(pp, msk, 0)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+
+	tests := []struct {
+		name   string
+		tamper func(ct *Ciphertext)
+	}{
+		{"payload", func(ct *Ciphertext) { ct.Payload[0] ^= 1 }},
+		{"header", func(ct *Ciphertext) { ct.Headers[1].B.Double() }},
+		{"subset", func(ct *Ciphertext) { ct.Subsets[0].J = ct.Subsets[0].I }},
+		{"truncated", func(ct *Ciphertext) { ct.Headers = ct.Headers[:1] }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ct, err := Encrypt(pp, []int{3, 6}, msg)
+			if err != nil {
+				t.Fatalf("Encrypt failed: %v", err)
+			}
+			tc.tamper(ct)
+			if _, err := Decrypt(pp, uk, ct); err != ErrInvalidPayload {
+				t.Fatalf("expected ErrInvalidPayload, but got %v", err)
+			}
+		})
+	}
+}
+
+func TestHeaderSize(t *testing.T) {
+	pp, _, err := Setup(10)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	for _, r := range []int{0, 1, 3, 10} {
+		revoked := make([]int, r)
+		for k := range revoked {
+			revoked[k] = k * 97
+		}
+		ct, err := Encrypt(pp, revoked, nil)
+		if err != nil {
+			t.Fatalf("Encrypt failed: %v", err)
+		}
+		if r > 0 && len(ct.Subsets) > 2*r-1 {
+			t.Fatalf("%d revoked: expected at most %d subsets, but got %d", r, 2*r-1, len(ct.Subsets))
+		}
+
+		// two uint32 node indices, then the bbg05 ciphertext: a uint32
+		// length, Gt, and uncompressed G1 and G2 points
+		perSubset := 8 + 4 + bls.GtSize + bls.G1Size + bls.G2Size
+		if ct.HeaderSize() != len(ct.Subsets)*perSubset {
+			t.Fatalf("%d revoked: expected %d bytes for %d subsets, but got %d", r, len(ct.Subsets)*perSubset, len(ct.Subsets), ct.HeaderSize())
+		}
+		t.Logf("%d revoked: %d subsets, %d bytes (%d per subset)", r, len(ct.Subsets), ct.HeaderSize(), perSubset)
+	}
+}
+
+func BenchmarkKeyGen(b *testing.B) {
+	pp, msk, err := Setup(10)
+	if err != nil {
+		b.Fatalf("Setup failed: %v", err)
+	}
+	for b.Loop() {
+		if _, err := KeyGen(pp, msk, 0); err != nil {
+			b.Fatalf("KeyGen failed: %v", err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package elgamal
 
 import (
+	"bytes"
 	"crypto/elliptic"
 	"testing"
 
@@ -38,6 +39,40 @@ func TestEncryptDecrypt(t *testing.T) {
 	}
 }
 
+func TestEncryptBytesDecryptBytes(t *testing.T) {
+	trials := []struct {
+		name  string
+		curve elliptic.Curve
+	}{
+		{"P-224", elliptic.P224()},
+		{"P-256", elliptic.P256()},
+		{"P-384", elliptic.P384()},
+		{"P-384/circl", circlp384.P384()},
+		{"P-521", elliptic.P521()},
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.  The quick brown fox jumps over the lazy dog.")
+
+	for _, trial := range trials {
+		t.Run(trial.name, func(t *testing.T) {
+			pp := NewPublicParams(trial.curve)
+
+			alicePK, aliceSK := KeyGen(pp)
+			cts, err := EncryptBytes(pp, alicePK, msg)
+			if err != nil {
+				t.Fatalf("EncryptBytes failed: %v", err)
+			}
+			got, err := DecryptBytes(pp, aliceSK, cts)
+			if err != nil {
+				t.Fatalf("DecryptBytes failed: %v", err)
+			}
+			if !bytes.Equal(msg, got) {
+				t.Fatalf("expected decrypted message to be %q, but got %q", msg, got)
+			}
+		})
+	}
+}
+
 func BenchmarkKeyGen(b *testing.B) {
 	trials := []struct {
 		name  string
@@ -0,0 +1,140 @@
+package ecc
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+)
+
+// This file implements Koblitz's try-and-increment method for reversibly
+// encoding a short byte string as a curve point.  The x-coordinate of the
+// point is, in big-endian order,
+//
+//	0x00 || len || data || zero padding || counter
+//
+// where len is a one-byte length prefix, data is at most [Capacity] bytes,
+// and counter is incremented from 0 until x is the x-coordinate of a point
+// on the curve.  The leading zero byte keeps x below the field prime.  Each
+// counter value succeeds with probability about 1/2, so the encoding fails
+// with probability about 2^-256.
+//
+// The method assumes a short Weierstrass curve y^2 = x^3 - 3x + b of prime
+// order, as with the NIST curves P-224, P-256, P-384 and P-521.
+
+var (
+	ErrChunkTooLong    = errors.New("ecc: message chunk exceeds the curve's capacity")
+	ErrEncodingFailed  = errors.New("ecc: failed to encode message as a curve point")
+	ErrInvalidEncoding = errors.New("ecc: point does not encode a message")
+)
+
+// overhead is the number of bytes in the x-coordinate that hold no data: the
+// leading zero byte, the length prefix, and the counter.
+const overhead = 3
+
+func fieldByteLen(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
+
+// Capacity returns the maximum number of message bytes that fit in one point.
+func Capacity(curve elliptic.Curve) int {
+	return min(fieldByteLen(curve)-overhead, 255)
+}
+
+// curveRHS returns x^3 - 3x + b mod p.
+func curveRHS(params *elliptic.CurveParams, x *big.Int) *big.Int {
+	x3 := new(big.Int).Mul(x, x)
+	x3.Mul(x3, x)
+
+	threeX := new(big.Int).Lsh(x, 1)
+	threeX.Add(threeX, x)
+
+	x3.Sub(x3, threeX)
+	x3.Add(x3, params.B)
+	x3.Mod(x3, params.P)
+	return x3
+}
+
+// EncodeChunk encodes at most [Capacity] bytes as a point.
+func EncodeChunk(curve elliptic.Curve, chunk []byte) (*Point, error) {
+	if len(chunk) > Capacity(curve) {
+		return nil, ErrChunkTooLong
+	}
+
+	params := curve.Params()
+	buf := make([]byte, fieldByteLen(curve))
+	buf[1] = byte(len(chunk))
+	copy(buf[2:], chunk)
+
+	for ctr := 0; ctr < 256; ctr++ {
+		buf[len(buf)-1] = byte(ctr)
+		x := new(big.Int).SetBytes(buf)
+		y := new(big.Int).ModSqrt(curveRHS(params, x), params.P)
+		if y == nil {
+			continue
+		}
+		if curve.IsOnCurve(x, y) {
+			return &Point{X: x, Y: y}, nil
+		}
+	}
+
+	return nil, ErrEncodingFailed
+}
+
+// DecodeChunk is the inverse of [EncodeChunk].
+func DecodeChunk(curve elliptic.Curve, p *Point) ([]byte, error) {
+	if p.X == nil || p.X.Sign() < 0 || p.X.BitLen() > 8*fieldByteLen(curve) {
+		return nil, ErrInvalidEncoding
+	}
+
+	buf := make([]byte, fieldByteLen(curve))
+	p.X.FillBytes(buf)
+
+	n := int(buf[1])
+	if buf[0] != 0 || n > Capacity(curve) {
+		return nil, ErrInvalidEncoding
+	}
+	// the padding must be zero, so that each chunk has one encoding (up to
+	// the counter)
+	for _, b := range buf[2+n : len(buf)-1] {
+		if b != 0 {
+			return nil, ErrInvalidEncoding
+		}
+	}
+
+	chunk := make([]byte, n)
+	copy(chunk, buf[2:2+n])
+	return chunk, nil
+}
+
+// EncodeMessage splits msg into chunks of at most [Capacity] bytes and
+// encodes each as a point.  An empty message encodes as a single point.
+func EncodeMessage(curve elliptic.Curve, msg []byte) ([]*Point, error) {
+	c := Capacity(curve)
+	n := max(1, (len(msg)+c-1)/c)
+
+	pts := make([]*Point, n)
+	for i := 0; i < n; i++ {
+		lo := i * c
+		hi := min(lo+c, len(msg))
+		p, err := EncodeChunk(curve, msg[lo:hi])
+		if err != nil {
+			return nil, err
+		}
+		pts[i] = p
+	}
+
+	return pts, nil
+}
+
+// DecodeMessage is the inverse of [EncodeMessage].
+func DecodeMessage(curve elliptic.Curve, pts []*Point) ([]byte, error) {
+	msg := make([]byte, 0, len(pts)*Capacity(curve))
+	for _, p := range pts {
+		chunk, err := DecodeChunk(curve, p)
+		if err != nil {
+			return nil, err
+		}
+		msg = append(msg, chunk...)
+	}
+	return msg, nil
+}
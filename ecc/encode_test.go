@@ -0,0 +1,85 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+	"testing"
+
+	circlp384 "github.com/cloudflare/circl/ecc/p384"
+	"github.com/etclab/ncircl/util/bytesx"
+)
+
+func TestEncodeDecodeMessage(t *testing.T) {
+	trials := []struct {
+		name  string
+		curve elliptic.Curve
+	}{
+		{"P-224", elliptic.P224()},
+		{"P-256", elliptic.P256()},
+		{"P-384", elliptic.P384()},
+		{"P-384/circl", circlp384.P384()},
+		{"P-521", elliptic.P521()},
+	}
+
+	for _, trial := range trials {
+		c := Capacity(trial.curve)
+		for _, n := range []int{0, 1, c - 1, c, c + 1, 3*c + 7} {
+			t.Run(fmt.Sprintf("%s/%d", trial.name, n), func(t *testing.T) {
+				msg := bytesx.Random(n)
+				pts, err := EncodeMessage(trial.curve, msg)
+				if err != nil {
+					t.Fatalf("EncodeMessage failed: %v", err)
+				}
+				for _, p := range pts {
+					if !trial.curve.IsOnCurve(p.X, p.Y) {
+						t.Fatal("EncodeMessage produced a point that is not on the curve")
+					}
+				}
+				got, err := DecodeMessage(trial.curve, pts)
+				if err != nil {
+					t.Fatalf("DecodeMessage failed: %v", err)
+				}
+				if !bytes.Equal(msg, got) {
+					t.Fatalf("expected decoded message to be %x, but got %x", msg, got)
+				}
+			})
+		}
+	}
+}
+
+func TestEncodeChunk_tooLong(t *testing.T) {
+	curve := elliptic.P256()
+	_, err := EncodeChunk(curve, make([]byte, Capacity(curve)+1))
+	if err != ErrChunkTooLong {
+		t.Fatalf("expected ErrChunkTooLong, but got %v", err)
+	}
+}
+
+func TestDecodeChunk_invalid(t *testing.T) {
+	curve := elliptic.P256()
+	// the generator's x-coordinate does not have a leading zero byte
+	params := curve.Params()
+	_, err := DecodeChunk(curve, &Point{X: params.Gx, Y: params.Gy})
+	if err != ErrInvalidEncoding {
+		t.Fatalf("expected ErrInvalidEncoding, but got %v", err)
+	}
+}
+
+func TestDecodeChunk_nonZeroPadding(t *testing.T) {
+	curve := elliptic.P256()
+	p, err := EncodeChunk(curve, []byte("abc"))
+	if err != nil {
+		t.Fatalf("EncodeChunk failed: %v", err)
+	}
+
+	buf := make([]byte, fieldByteLen(curve))
+	p.X.FillBytes(buf)
+	buf[2+3] = 1
+	padded := &Point{X: new(big.Int).SetBytes(buf), Y: p.Y}
+
+	if _, err := DecodeChunk(curve, padded); err != ErrInvalidEncoding {
+		t.Fatalf("expected ErrInvalidEncoding, but got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package chk03
+
+import (
+	"errors"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/mu"
+	"github.com/etclab/ncircl/hibe/bbg05"
+	"github.com/etclab/ncircl/internal/bbgtree"
+)
+
+// MaxDepth is the maximum depth of the tree of time periods.
+const MaxDepth = 63
+
+var (
+	ErrInvalidDepth  = errors.New("chk03: invalid tree depth")
+	ErrInvalidPeriod = errors.New("chk03: invalid time period")
+	ErrExpiredPeriod = errors.New("chk03: key has evolved past the ciphertext's time period")
+	ErrLastPeriod    = errors.New("chk03: key is for the last time period")
+)
+
+type PublicParams struct {
+	// Depth is the depth of the tree of time periods; there are 2^Depth
+	// periods.
+	Depth int
+	HIBE  *bbg05.PublicParams
+}
+
+func (pp *PublicParams) NumPeriods() uint64 {
+	return 1 << pp.Depth
+}
+
+// SecretKey is the receiver's key for the time period Period.
+type SecretKey struct {
+	Period uint64
+
+	// stack[len(stack)-1] is the key for Period's leaf; the other entries
+	// are the keys for the right siblings of the nodes on Period's path,
+	// deepest last, so that they are in the order of the periods that they
+	// cover, from the top of the stack.
+	stack []*bbgtree.Key
+}
+
+// descend pushes the keys for the leftmost leaf below node, and for the right
+// siblings along the way, and erases the keys for the inner nodes.
+func (sk *SecretKey) descend(pp *PublicParams, node *bbgtree.Key) {
+	for node.Depth < pp.Depth {
+		right := node.Child(pp.HIBE, 1)
+		left := node.Child(pp.HIBE, 0)
+		node.Erase()
+		sk.stack = append(sk.stack, right)
+		node = left
+	}
+	sk.stack = append(sk.stack, node)
+}
+
+// KeyGen creates the public parameters and the secret key for period 0 of a
+// tree of the given depth.  No master key survives KeyGen: the key for the
+// root, from which all others derive, is erased on the way down to period
+// 0's leaf.
+func KeyGen(depth int) (*PublicParams, *SecretKey, error) {
+	if depth < 1 || depth > MaxDepth {
+		return nil, nil, ErrInvalidDepth
+	}
+
+	hibePP, root := bbgtree.Setup(depth)
+	pp := &PublicParams{Depth: depth, HIBE: hibePP}
+
+	sk := &SecretKey{Period: 0}
+	sk.descend(pp, root)
+	return pp, sk, nil
+}
+
+// Update evolves the key to the next period, and erases the key for the
+// current period.
+func (sk *SecretKey) Update(pp *PublicParams) error {
+	if sk.Period+1 >= pp.NumPeriods() {
+		return ErrLastPeriod
+	}
+
+	leaf := sk.stack[len(sk.stack)-1]
+	next := sk.stack[len(sk.stack)-2]
+	sk.stack = sk.stack[:len(sk.stack)-2]
+	leaf.Erase()
+
+	sk.descend(pp, next)
+	sk.Period++
+	return nil
+}
+
+type Ciphertext struct {
+	Period uint64
+	CT     *bbg05.Ciphertext
+}
+
+func Encrypt(pp *PublicParams, period uint64, m *bls.Gt) (*Ciphertext, error) {
+	if period >= pp.NumPeriods() {
+		return nil, ErrInvalidPeriod
+	}
+
+	ct, err := bbg05.Encrypt(pp.HIBE, bbgtree.NodeId(pp.HIBE, pp.Depth, period), m)
+	if err != nil {
+		return nil, err
+	}
+	return &Ciphertext{Period: period, CT: ct}, nil
+}
+
+// Decrypt decrypts a ciphertext for the key's period or for a later one.  It
+// returns [ErrExpiredPeriod] for a ciphertext from an earlier period.
+func Decrypt(pp *PublicParams, sk *SecretKey, ct *Ciphertext) (*bls.Gt, error) {
+	if ct.Period >= pp.NumPeriods() {
+		return nil, ErrInvalidPeriod
+	}
+	if ct.Period < sk.Period {
+		return nil, ErrExpiredPeriod
+	}
+
+	for i := len(sk.stack) - 1; i >= 0; i-- {
+		if sk.stack[i].Contains(pp.HIBE, ct.Period) {
+			return sk.stack[i].Decrypt(pp.HIBE, ct.Period, ct.CT), nil
+		}
+	}
+	mu.Panicf("no key covers period %d", ct.Period)
+	return nil, nil
+}
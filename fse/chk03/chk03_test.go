@@ -0,0 +1,124 @@
+package chk03
+
+import (
+	"testing"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/hibe/bbg05"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestUpdate(t *testing.T) {
+	pp, sk, err := KeyGen(3)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	ms := make([]*bls.Gt, pp.NumPeriods())
+	cts := make([]*Ciphertext, pp.NumPeriods())
+	for i := range cts {
+		ms[i] = blspairing.NewRandomGt()
+		cts[i], err = Encrypt(pp, uint64(i), ms[i])
+		if err != nil {
+			t.Fatalf("Encrypt failed: %v", err)
+		}
+	}
+
+	for period := uint64(0); period < pp.NumPeriods(); period++ {
+		if sk.Period != period {
+			t.Fatalf("expected key for period %d, but got %d", period, sk.Period)
+		}
+		if len(sk.stack) > pp.Depth+1 {
+			t.Fatalf("expected at most %d keys, but got %d", pp.Depth+1, len(sk.stack))
+		}
+
+		for i, ct := range cts {
+			m, err := Decrypt(pp, sk, ct)
+			if uint64(i) < period {
+				if err != ErrExpiredPeriod {
+					t.Fatalf("period %d: expected ErrExpiredPeriod for period %d, but got %v", period, i, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("Decrypt failed: %v", err)
+			}
+			if !m.IsEqual(ms[i]) {
+				t.Fatalf("period %d: decryption of period %d failed", period, i)
+			}
+		}
+
+		err := sk.Update(pp)
+		if period == pp.NumPeriods()-1 {
+			if err != ErrLastPeriod {
+				t.Fatalf("expected ErrLastPeriod, but got %v", err)
+			}
+		} else if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+	}
+}
+
+// Forward security: after an update, no key in the secret key lies on the
+// path to an earlier period, so an attacker who steals the key cannot
+// derive a key for that period.
+func TestForwardSecurity(t *testing.T) {
+	pp, sk, err := KeyGen(4)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	m := blspairing.NewRandomGt()
+	ct, err := Encrypt(pp, 5, m)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	for range 6 {
+		if err := sk.Update(pp); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+	}
+
+	for _, nk := range sk.stack {
+		for period := range sk.Period {
+			if nk.Contains(pp.HIBE, period) {
+				t.Fatalf("key for node (%d, %d) covers past period %d", nk.Depth, nk.Prefix, period)
+			}
+		}
+		// try the stolen key directly on the old ciphertext anyway
+		if bbg05.Decrypt(pp.HIBE, nk.SK, ct.CT).IsEqual(m) {
+			t.Fatal("a stolen key decrypted a ciphertext from an earlier period")
+		}
+	}
+}
+
+func TestKeyGen_invalidDepth(t *testing.T) {
+	for _, depth := range []int{0, MaxDepth + 1} {
+		if _, _, err := KeyGen(depth); err != ErrInvalidDepth {
+			t.Fatalf("depth %d: expected ErrInvalidDepth, but got %v", depth, err)
+		}
+	}
+}
+
+func TestEncrypt_invalidPeriod(t *testing.T) {
+	pp, _, err := KeyGen(2)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+	if _, err := Encrypt(pp, pp.NumPeriods(), blspairing.NewRandomGt()); err != ErrInvalidPeriod {
+		t.Fatalf("expected ErrInvalidPeriod, but got %v", err)
+	}
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	pp, sk, err := KeyGen(32)
+	if err != nil {
+		b.Fatalf("KeyGen failed: %v", err)
+	}
+	for b.Loop() {
+		if err := sk.Update(pp); err != nil {
+			b.Fatalf("Update failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Package chk03 implements the forward-secure public-key encryption scheme
+// from the [paper]:
+//
+//	@inproceedings{03-eurocrypt-forward_secure_pke,
+//	    title = {A Forward-Secure Public-Key Encryption Scheme},
+//	    author = {Canetti, Ran and Halevi, Shai and Katz, Jonathan},
+//	    booktitle = {International Conference on the Theory and Applications of Cryptographic Techniques (EUROCRYPT)},
+//	    year = {2003},
+//	}
+//
+// The public key is fixed, but the secret key evolves: at the end of each
+// time period, the receiver calls [SecretKey.Update], which derives the key
+// for the next period and erases the key for the current one.  An adversary
+// that steals the secret key during period t cannot decrypt ciphertexts for
+// earlier periods.
+//
+// Section 3 of the paper builds the scheme from a binary-tree encryption
+// scheme, which a HIBE instantiates.  The time periods are the leaves of a
+// binary tree of depth d, and the node with path b_1 ... b_k is the
+// [bbg05] identity (b_1, ..., b_k).  The secret key for period t is the
+// HIBE key for t's leaf, together with the HIBE keys for the right siblings
+// of the nodes on t's path: these cover exactly the periods after t.  The
+// secret key thus holds at most d+1 HIBE keys, and since [bbg05] ciphertexts
+// have constant size, so do this scheme's.
+//
+// # Changes from Paper
+// The paper uses every node of the tree, in preorder, as a time period; this
+// package uses only the leaves, so there are 2^d periods rather than
+// 2^(d+1) - 1.  Messages are elements of Gt, as in [bbg05].  [Decrypt] also
+// decrypts ciphertexts for future periods, by deriving a temporary key from
+// the secret key.
+//
+// In Go, erasing a key cannot guarantee that no copy of it remains in
+// memory; [SecretKey.Update] overwrites the group elements of the keys that
+// it discards, and drops all references to them.
+//
+// [paper]: https://eprint.iacr.org/2003/083.pdf
+package chk03
@@ -0,0 +1,38 @@
+package chk03_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/etclab/ncircl/fse/chk03"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// Example shows how a key that is stolen in one period cannot decrypt a
+// message from an earlier period.
+func Example() {
+	pp, sk, err := chk03.KeyGen(8)
+	if err != nil {
+		log.Fatalf("failed to generate key: %v", err)
+	}
+
+	m := blspairing.NewRandomGt()
+	ct, err := chk03.Encrypt(pp, 0, m)
+	if err != nil {
+		log.Fatalf("failed to encrypt: %v", err)
+	}
+
+	got, err := chk03.Decrypt(pp, sk, ct)
+	fmt.Println(got.IsEqual(m), err)
+
+	// Move to period 1, erasing the key for period 0.
+	if err := sk.Update(pp); err != nil {
+		log.Fatalf("failed to update key: %v", err)
+	}
+
+	_, err = chk03.Decrypt(pp, sk, ct)
+	fmt.Println(err)
+	// Output:
+	// true <nil>
+	// chk03: key has evolved past the ciphertext's time period
+}
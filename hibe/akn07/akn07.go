@@ -1,6 +1,7 @@
 package akn07
 
 import (
+	"bytes"
 	"errors"
 
 	bls "github.com/cloudflare/circl/ecc/bls12381"
@@ -11,6 +12,7 @@ var (
 	ErrPatternExceedsMaxDepth = errors.New("Pattern is longer than the max depth")
 	ErrPatternInvalidDepth    = errors.New("Pattern must be of max depth length")
 	ErrPatternDoesNotMatch    = errors.New("Pattern does not match parent")
+	ErrInvalidPrivateKey      = errors.New("akn07: invalid private key")
 )
 
 // This is also called the master public key (mpk)
@@ -72,6 +74,11 @@ func Setup(maxDepth int) (*PublicParams, *MasterKey) {
 
 type Pattern struct {
 	Ps []*bls.Scalar // len MaxDepth
+
+	// Components are the strings that the fixed slots were hashed from, for
+	// display; a component is nil for a free slot, and for a fixed slot
+	// whose string is unknown (as after UnmarshalBinary).
+	Components [][]byte
 }
 
 func NewPattern(pp *PublicParams, components [][]byte) (*Pattern, error) {
@@ -80,11 +87,13 @@ func NewPattern(pp *PublicParams, components [][]byte) (*Pattern, error) {
 	}
 	pattern := new(Pattern)
 	pattern.Ps = make([]*bls.Scalar, len(components))
+	pattern.Components = make([][]byte, len(components))
 	for i := 0; i < len(components); i++ {
 		if components[i] == nil {
 			pattern.Ps[i] = nil
 		} else {
 			pattern.Ps[i] = blspairing.HashBytesToScalar(components[i])
+			pattern.Components[i] = bytes.Clone(components[i])
 		}
 	}
 
@@ -115,6 +124,12 @@ func (p *Pattern) Clone() *Pattern {
 		}
 		newP.Ps[i] = blspairing.CloneScalar(p.Ps[i])
 	}
+	if p.Components != nil {
+		newP.Components = make([][]byte, len(p.Components))
+		for i, c := range p.Components {
+			newP.Components[i] = bytes.Clone(c)
+		}
+	}
 	return newP
 }
 
@@ -204,6 +219,26 @@ func (sk *PrivateKey) Clone() *PrivateKey {
 	}
 }
 
+// Validate checks that the key has the shape of a key for its pattern: a B
+// for each free slot of the pattern, and none for its fixed slots.  Keys
+// from [KeyGen] and [KeyDer] are always valid; keys from
+// [PrivateKey.UnmarshalBinary] are not checked against the public
+// parameters, and should be validated before they are used.
+func (sk *PrivateKey) Validate(pp *PublicParams) error {
+	if sk.K0 == nil || sk.K1 == nil || sk.Pattern == nil {
+		return ErrInvalidPrivateKey
+	}
+	if sk.Pattern.Depth() != pp.MaxDepth || len(sk.Bs) != pp.MaxDepth {
+		return ErrInvalidPrivateKey
+	}
+	for i, b := range sk.Bs {
+		if (b == nil) != (sk.Pattern.Ps[i] != nil) {
+			return ErrInvalidPrivateKey
+		}
+	}
+	return nil
+}
+
 func KeyGen(pp *PublicParams, msk *MasterKey, pattern *Pattern) (*PrivateKey, error) {
 	if pattern.Depth() != pp.MaxDepth {
 		return nil, ErrPatternInvalidDepth
@@ -243,6 +278,9 @@ func KeyGen(pp *PublicParams, msk *MasterKey, pattern *Pattern) (*PrivateKey, er
 }
 
 func KeyDer(pp *PublicParams, parentSk *PrivateKey, childPattern *Pattern) (*PrivateKey, error) {
+	if err := parentSk.Validate(pp); err != nil {
+		return nil, err
+	}
 	if childPattern.Depth() != pp.MaxDepth {
 		return nil, ErrPatternInvalidDepth
 	}
@@ -291,6 +329,46 @@ func KeyDer(pp *PublicParams, parentSk *PrivateKey, childPattern *Pattern) (*Pri
 	}, nil
 }
 
+// patternHash returns prod_{i fixed} h_i^{P_i} g_3.
+func patternHash(pp *PublicParams, pattern *Pattern) *bls.G1 {
+	agg := blspairing.NewG1Identity()
+	var tmp bls.G1
+	for _, i := range pattern.FixedIndices() {
+		tmp.ScalarMult(pattern.Ps[i], pp.Hs[i])
+		agg.Add(agg, &tmp)
+	}
+	agg.Add(agg, pp.G3)
+	return agg
+}
+
+// Rerandomize returns a copy of sk with fresh randomness: the key for the
+// same pattern, with r replaced by r + t for a random t.  The result has the
+// same distribution as a key from [KeyGen].  It returns
+// [ErrInvalidPrivateKey] if sk fails [PrivateKey.Validate].
+func Rerandomize(pp *PublicParams, sk *PrivateKey) (*PrivateKey, error) {
+	if err := sk.Validate(pp); err != nil {
+		return nil, err
+	}
+
+	t := blspairing.NewRandomScalar()
+	out := sk.Clone()
+
+	tmp := patternHash(pp, sk.Pattern)
+	tmp.ScalarMult(t, tmp)
+	out.K0.Add(out.K0, tmp)
+
+	var tmp2 bls.G2
+	tmp2.ScalarMult(t, pp.G)
+	out.K1.Add(out.K1, &tmp2)
+
+	for _, i := range sk.Pattern.FreeIndices() {
+		tmp.ScalarMult(t, pp.Hs[i])
+		out.Bs[i].Add(out.Bs[i], tmp)
+	}
+
+	return out, nil
+}
+
 type Ciphertext struct {
 	X *bls.Gt
 	Y *bls.G2
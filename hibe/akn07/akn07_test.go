@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	bls "github.com/cloudflare/circl/ecc/bls12381"
 	"github.com/etclab/ncircl/util/blspairing"
 )
 
@@ -600,3 +601,128 @@ func TestSignatureSerialization(t *testing.T) {
 		})
 	}
 }
+
+// wellFormed checks that sk has the form of a KeyGen key for its pattern,
+// for some r: K1 = g^r, K0 = g_2^alpha * (prod_{i fixed} h_i^{P_i} g_3)^r,
+// and B_i = h_i^r for each free slot i.
+func wellFormed(pp *PublicParams, msk *MasterKey, sk *PrivateKey) bool {
+	neg := blspairing.CloneG1(msk.G2toAlpha)
+	neg.Neg()
+	k0 := new(bls.G1)
+	k0.Add(sk.K0, neg)
+	if !bls.Pair(k0, pp.G).IsEqual(bls.Pair(patternHash(pp, sk.Pattern), sk.K1)) {
+		return false
+	}
+
+	for i, b := range sk.Bs {
+		if (b == nil) != (sk.Pattern.Ps[i] != nil) {
+			return false
+		}
+		if b != nil && !bls.Pair(b, pp.G).IsEqual(bls.Pair(pp.Hs[i], sk.K1)) {
+			return false
+		}
+	}
+	return true
+}
+
+// A key from KeyDer or Rerandomize must have the distribution of a key from
+// KeyGen: it must be well-formed, and its randomness r must be fresh and
+// independent of the parent's.  Since KeyDer and Rerandomize add a fresh
+// uniform t to the parent's r, the second property holds if r changes; the
+// test checks both.
+func TestKeyDistribution(t *testing.T) {
+	pp, msk := Setup(DefaultDepth)
+
+	parentPattern, err := ParsePattern(pp, "acme/*/eng")
+	if err != nil {
+		t.Fatalf("failed to parse pattern: %v", err)
+	}
+	childPattern, err := ParsePattern(pp, "acme/nyc/eng/*/alice")
+	if err != nil {
+		t.Fatalf("failed to parse pattern: %v", err)
+	}
+
+	parent, err := KeyGen(pp, msk, parentPattern)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+	generated, err := KeyGen(pp, msk, childPattern)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+	derived, err := KeyDer(pp, parent, childPattern)
+	if err != nil {
+		t.Fatalf("KeyDer failed: %v", err)
+	}
+	derived2, err := KeyDer(pp, parent, childPattern)
+	if err != nil {
+		t.Fatalf("KeyDer failed: %v", err)
+	}
+	rerandomized, err := Rerandomize(pp, derived)
+	if err != nil {
+		t.Fatalf("Rerandomize failed: %v", err)
+	}
+
+	keys := map[string]*PrivateKey{
+		"parent":       parent,
+		"generated":    generated,
+		"derived":      derived,
+		"derived2":     derived2,
+		"rerandomized": rerandomized,
+	}
+	for name, sk := range keys {
+		if !wellFormed(pp, msk, sk) {
+			t.Fatalf("%s key is not well-formed", name)
+		}
+	}
+
+	bad := derived.Clone()
+	bad.K1.Double()
+	if wellFormed(pp, msk, bad) {
+		t.Fatalf("a malformed key passed the well-formedness check")
+	}
+
+	for name1, sk1 := range keys {
+		for name2, sk2 := range keys {
+			if name1 < name2 && sk1.K1.IsEqual(sk2.K1) {
+				t.Fatalf("%s and %s keys share randomness", name1, name2)
+			}
+		}
+	}
+
+	m := blspairing.NewRandomGt()
+	ct, err := Encrypt(pp, childPattern, m)
+	if err != nil {
+		t.Fatalf("failed to encrypt: %v", err)
+	}
+	if !Decrypt(pp, rerandomized, ct).IsEqual(m) {
+		t.Fatalf("decryption with a rerandomized key failed")
+	}
+}
+
+// Keys do not alias the patterns they were created from, or each other.
+func TestKeyDeepCopy(t *testing.T) {
+	pp, msk := Setup(DefaultDepth)
+
+	pattern, err := ParsePattern(pp, "acme/*/eng")
+	if err != nil {
+		t.Fatalf("failed to parse pattern: %v", err)
+	}
+	sk, err := KeyGen(pp, msk, pattern)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pattern.Ps[0].SetUint64(7)
+	pattern.Components[0][0] = 'X'
+	if sk.Pattern.String() != "acme/*/eng" || !wellFormed(pp, msk, sk) {
+		t.Fatalf("KeyGen key aliases its pattern")
+	}
+
+	clone := sk.Clone()
+	clone.K0.Double()
+	clone.Bs[1].Double()
+	clone.Pattern.Ps[2].SetUint64(7)
+	if !wellFormed(pp, msk, sk) {
+		t.Fatalf("Clone aliases the original key")
+	}
+}
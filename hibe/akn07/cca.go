@@ -0,0 +1,142 @@
+package akn07
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/mu"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// The CCA-secure mode applies the Canetti-Halevi-Katz transform (EUROCRYPT
+// 2004): the sender creates a one-time ed25519 key pair, fixes the last slot
+// of the recipient's pattern to the verification key, encrypts, and signs
+// the ciphertext.  The recipient checks the signature, checks that the
+// ciphertext is well-formed for that pattern, and decrypts with a key that
+// it derives for the last slot.  The mode thus uses the same slot as
+// [Sign], and the recipient's pattern must leave that slot free.
+//
+// Since a signature on a message scalar m is also a decryption key for the
+// pattern whose last slot is m, a key holder must not sign the scalar that a
+// verification key hashes to; the hashes of verification keys are
+// domain-separated from other hashes for this reason.
+
+var (
+	ErrInvalidSignature  = errors.New("akn07: invalid signature")
+	ErrInvalidCiphertext = errors.New("akn07: invalid ciphertext")
+	ErrLastSlotFixed     = errors.New("akn07: pattern fixes the last slot")
+)
+
+var vkDomainSepTag = []byte("akn07-cca-vk")
+
+// vkComponent hashes a verification key to a pattern component.
+func vkComponent(vk ed25519.PublicKey) *bls.Scalar {
+	buf := make([]byte, 0, len(vkDomainSepTag)+len(vk))
+	buf = append(buf, vkDomainSepTag...)
+	buf = append(buf, vk...)
+	return blspairing.HashBytesToScalar(buf)
+}
+
+// vkPattern returns pattern with its last slot fixed to vk.
+func vkPattern(pattern *Pattern, vk ed25519.PublicKey) *Pattern {
+	p := pattern.Clone()
+	p.Ps[len(p.Ps)-1] = vkComponent(vk)
+	if p.Components != nil {
+		p.Components[len(p.Components)-1] = nil
+	}
+	return p
+}
+
+func lastSlotFree(pattern *Pattern) bool {
+	return pattern.Depth() > 0 && pattern.Ps[pattern.Depth()-1] == nil
+}
+
+type CCACiphertext struct {
+	VK  ed25519.PublicKey
+	CT  *Ciphertext
+	Sig []byte
+}
+
+func (ct *CCACiphertext) MessageToSign() []byte {
+	m := make([]byte, 0, 1024)
+	m = append(m, blspairing.GtToBytes(ct.CT.X)...)
+	m = append(m, ct.CT.Y.Bytes()...)
+	m = append(m, ct.CT.Z.Bytes()...)
+	return m
+}
+
+// Check verifies the signature, and checks that the ciphertext is
+// well-formed for pattern with its last slot fixed to the verification key:
+// e(Z, g) = e(prod_{i fixed} h_i^{P_i} g_3, Y).
+func (ct *CCACiphertext) Check(pp *PublicParams, pattern *Pattern) error {
+	if len(ct.VK) != ed25519.PublicKeySize || !ed25519.Verify(ct.VK, ct.MessageToSign(), ct.Sig) {
+		return ErrInvalidSignature
+	}
+
+	if pattern.Depth() != pp.MaxDepth {
+		return ErrPatternInvalidDepth
+	}
+	if !lastSlotFree(pattern) {
+		return ErrLastSlotFixed
+	}
+
+	lhs := bls.Pair(ct.CT.Z, pp.G)
+	rhs := bls.Pair(patternHash(pp, vkPattern(pattern, ct.VK)), ct.CT.Y)
+	if !lhs.IsEqual(rhs) {
+		return ErrInvalidCiphertext
+	}
+
+	return nil
+}
+
+// EncryptCCA encrypts m to pattern with the CCA-secure mode.  The pattern
+// must leave the last slot free.
+func EncryptCCA(pp *PublicParams, pattern *Pattern, m *bls.Gt) (*CCACiphertext, error) {
+	if pattern.Depth() != pp.MaxDepth {
+		return nil, ErrPatternInvalidDepth
+	}
+	if !lastSlotFree(pattern) {
+		return nil, ErrLastSlotFixed
+	}
+
+	vk, ssk, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		mu.Panicf("ed25519.GenerateKey failed: %v", err)
+	}
+
+	inner, err := Encrypt(pp, vkPattern(pattern, vk), m)
+	if err != nil {
+		return nil, err
+	}
+
+	ct := &CCACiphertext{
+		VK: vk,
+		CT: inner,
+	}
+	ct.Sig = ed25519.Sign(ssk, ct.MessageToSign())
+
+	return ct, nil
+}
+
+// DecryptCCA checks the ciphertext against the key's pattern, and returns
+// [ErrInvalidSignature] or [ErrInvalidCiphertext] if the ciphertext was
+// tampered with or was encrypted to another pattern.  As with [Decrypt],
+// the ciphertext must have been encrypted to exactly the key's pattern.
+func DecryptCCA(pp *PublicParams, sk *PrivateKey, ct *CCACiphertext) (*bls.Gt, error) {
+	if err := sk.Validate(pp); err != nil {
+		return nil, err
+	}
+	if err := ct.Check(pp, sk.Pattern); err != nil {
+		return nil, err
+	}
+
+	// Fixing the last slot to the verification key only changes K0, by
+	// vk * B_L; K1 stays as it is.
+	k0 := new(bls.G1)
+	k0.ScalarMult(vkComponent(ct.VK), sk.Bs[len(sk.Bs)-1])
+	k0.Add(k0, sk.K0)
+
+	return Decrypt(pp, &PrivateKey{K0: k0, K1: sk.K1}, ct.CT), nil
+}
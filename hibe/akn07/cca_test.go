@@ -0,0 +1,168 @@
+package akn07
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestDecryptCCA(t *testing.T) {
+	pp, msk := Setup(DefaultDepth)
+
+	alicePattern, err := NewPatternFromStrings(pp, []string{"com", "example", "alice"})
+	if err != nil {
+		t.Fatalf("failed to create pattern: %v", err)
+	}
+	aliceKey, err := KeyGen(pp, msk, alicePattern)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	m := blspairing.NewRandomGt()
+	ct, err := EncryptCCA(pp, alicePattern, m)
+	if err != nil {
+		t.Fatalf("failed to encrypt: %v", err)
+	}
+
+	got, err := DecryptCCA(pp, aliceKey, ct)
+	if err != nil {
+		t.Fatalf("failed to decrypt: %v", err)
+	}
+	if !got.IsEqual(m) {
+		t.Fatalf("decryption failed")
+	}
+
+	bobPattern, err := NewPatternFromStrings(pp, []string{"com", "example", "bob"})
+	if err != nil {
+		t.Fatalf("failed to create pattern: %v", err)
+	}
+	bobKey, err := KeyGen(pp, msk, bobPattern)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	if _, err := DecryptCCA(pp, bobKey, ct); err != ErrInvalidCiphertext {
+		t.Fatalf("expected ErrInvalidCiphertext for another recipient, but got %v", err)
+	}
+
+	full := make([]string, DefaultDepth)
+	for i := range full {
+		full[i] = "x"
+	}
+	fullPattern, err := NewPatternFromStrings(pp, full)
+	if err != nil {
+		t.Fatalf("failed to create pattern: %v", err)
+	}
+	if _, err := EncryptCCA(pp, fullPattern, m); err != ErrLastSlotFixed {
+		t.Fatalf("expected ErrLastSlotFixed, but got %v", err)
+	}
+}
+
+// A decoded key whose Bs do not match its pattern is rejected rather than
+// causing a panic.
+func TestDecryptCCA_invalidKey(t *testing.T) {
+	pp, msk := Setup(DefaultDepth)
+
+	pattern, err := NewPatternFromStrings(pp, []string{"com", "example", "alice"})
+	if err != nil {
+		t.Fatalf("failed to create pattern: %v", err)
+	}
+	sk, err := KeyGen(pp, msk, pattern)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	if err := sk.Validate(pp); err != nil {
+		t.Fatalf("Validate rejected a valid key: %v", err)
+	}
+
+	ct, err := EncryptCCA(pp, pattern, blspairing.NewRandomGt())
+	if err != nil {
+		t.Fatalf("failed to encrypt: %v", err)
+	}
+
+	last := pp.MaxDepth - 1
+	mutations := map[string]func(sk *PrivateKey){
+		"no Bs":         func(sk *PrivateKey) { sk.Bs = nil },
+		"short Bs":      func(sk *PrivateKey) { sk.Bs = sk.Bs[:last] },
+		"free slot nil": func(sk *PrivateKey) { sk.Bs[last] = nil },
+		"fixed slot B":  func(sk *PrivateKey) { sk.Bs[0] = blspairing.NewRandomG1() },
+	}
+	for name, mutate := range mutations {
+		bad := sk.Clone()
+		mutate(bad)
+		data, err := bad.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: MarshalBinary failed: %v", name, err)
+		}
+		decoded := new(PrivateKey)
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%s: UnmarshalBinary failed: %v", name, err)
+		}
+
+		if err := decoded.Validate(pp); err != ErrInvalidPrivateKey {
+			t.Fatalf("%s: expected ErrInvalidPrivateKey from Validate, but got %v", name, err)
+		}
+		if _, err := DecryptCCA(pp, decoded, ct); err != ErrInvalidPrivateKey {
+			t.Fatalf("%s: expected ErrInvalidPrivateKey from DecryptCCA, but got %v", name, err)
+		}
+		if _, err := KeyDer(pp, decoded, pattern); err != ErrInvalidPrivateKey {
+			t.Fatalf("%s: expected ErrInvalidPrivateKey from KeyDer, but got %v", name, err)
+		}
+		if _, err := Rerandomize(pp, decoded); err != ErrInvalidPrivateKey {
+			t.Fatalf("%s: expected ErrInvalidPrivateKey from Rerandomize, but got %v", name, err)
+		}
+	}
+}
+
+func TestDecryptCCA_mauled(t *testing.T) {
+	pp, msk := Setup(DefaultDepth)
+
+	alicePattern, err := NewPatternFromStrings(pp, []string{"com", "example", "alice"})
+	if err != nil {
+		t.Fatalf("failed to create pattern: %v", err)
+	}
+	aliceKey, err := KeyGen(pp, msk, alicePattern)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	// resign replaces the one-time key, as an attacker could
+	resign := func(ct *CCACiphertext) {
+		vk, ssk, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("failed to generate one-time key: %v", err)
+		}
+		ct.VK = vk
+		ct.Sig = ed25519.Sign(ssk, ct.MessageToSign())
+	}
+
+	tests := []struct {
+		name string
+		maul func(ct *CCACiphertext)
+		want error
+	}{
+		{"X", func(ct *CCACiphertext) { ct.CT.X.Mul(ct.CT.X, blspairing.NewRandomGt()) }, ErrInvalidSignature},
+		{"Y", func(ct *CCACiphertext) { ct.CT.Y.Double() }, ErrInvalidSignature},
+		{"Z", func(ct *CCACiphertext) { ct.CT.Z.Double() }, ErrInvalidSignature},
+		{"Sig", func(ct *CCACiphertext) { ct.Sig[0] ^= 1 }, ErrInvalidSignature},
+		{"VK+resign", resign, ErrInvalidCiphertext},
+		{"X+resign", func(ct *CCACiphertext) {
+			ct.CT.X.Mul(ct.CT.X, blspairing.NewRandomGt())
+			resign(ct)
+		}, ErrInvalidCiphertext},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ct, err := EncryptCCA(pp, alicePattern, blspairing.NewRandomGt())
+			if err != nil {
+				t.Fatalf("failed to encrypt: %v", err)
+			}
+			tc.maul(ct)
+			if _, err := DecryptCCA(pp, aliceKey, ct); err != tc.want {
+				t.Fatalf("expected %v, but got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -99,3 +99,72 @@ func ExampleSign() {
 	// signature valid: true
 	// message: "The quick brown fox jumps over the lazy dog."
 }
+
+// ExampleParsePattern shows the path syntax for patterns, and how to find
+// which patterns a key can derive.
+func ExampleParsePattern() {
+	pp, msk := akn07.Setup(6)
+
+	eng, err := akn07.ParsePattern(pp, "acme/*/eng")
+	if err != nil {
+		log.Fatalf("failed to parse pattern: %v", err)
+	}
+	key, err := akn07.KeyGen(pp, msk, eng)
+	if err != nil {
+		log.Fatalf("failed to generate key: %v", err)
+	}
+
+	var candidates []*akn07.Pattern
+	for _, path := range []string{"acme/nyc/eng/alice", "acme/nyc/ops/bob", `acme/*/eng/carol\/dave`} {
+		p, err := akn07.ParsePattern(pp, path)
+		if err != nil {
+			log.Fatalf("failed to parse pattern: %v", err)
+		}
+		candidates = append(candidates, p)
+	}
+
+	for _, p := range akn07.Derivable(key, candidates) {
+		fmt.Println(p)
+	}
+	// Output:
+	// acme/nyc/eng/alice
+	// acme/*/eng/carol\/dave
+}
+
+// ExampleSignWithPattern shows a signature that carries its signer's
+// pattern, so that a verifier needs only the public parameters.
+func ExampleSignWithPattern() {
+	pp, msk := akn07.Setup(5)
+
+	pattern, err := akn07.ParsePattern(pp, "com/example/alice")
+	if err != nil {
+		log.Fatalf("failed to parse pattern: %v", err)
+	}
+	key, err := akn07.KeyGen(pp, msk, pattern)
+	if err != nil {
+		log.Fatalf("failed to generate key: %v", err)
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	sig, err := akn07.SignWithPattern(pp, key, msg)
+	if err != nil {
+		log.Fatalf("failed to sign: %v", err)
+	}
+	blob, err := sig.MarshalBinary()
+	if err != nil {
+		log.Fatalf("failed to marshal signature: %v", err)
+	}
+
+	// verifier
+	got := new(akn07.PatternSignature)
+	if err := got.UnmarshalBinary(blob); err != nil {
+		log.Fatalf("failed to unmarshal signature: %v", err)
+	}
+	signer, err := got.Verify(pp, msg)
+	if err != nil {
+		log.Fatalf("failed to verify signature: %v", err)
+	}
+	fmt.Println(len(signer.FixedIndices()), "fixed slots")
+	// Output:
+	// 3 fixed slots
+}
@@ -0,0 +1,83 @@
+package akn07
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptBytesDecryptBytes(t *testing.T) {
+	pp, msk := Setup(DefaultDepth)
+
+	for _, path := range []string{"org/dept/user", "org/*/user"} {
+		pattern, err := ParsePattern(pp, path)
+		if err != nil {
+			t.Fatalf("failed to parse pattern: %v", err)
+		}
+		sk, err := KeyGen(pp, msk, pattern)
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+
+		for _, n := range []int{0, 1, 32, 1000} {
+			msg := bytes.Repeat([]byte{'a'}, n)
+			ct, err := EncryptBytes(pp, pattern, msg)
+			if err != nil {
+				t.Fatalf("EncryptBytes failed: %v", err)
+			}
+			got, err := DecryptBytes(pp, sk, ct)
+			if err != nil {
+				t.Fatalf("DecryptBytes failed: %v", err)
+			}
+			if !bytes.Equal(got, msg) {
+				t.Fatalf("%s: DecryptBytes did not produce the original %d-byte message", path, n)
+			}
+		}
+	}
+}
+
+func TestDecryptBytes_tampered(t *testing.T) {
+	pp, msk := Setup(DefaultDepth)
+
+	pattern, err := ParsePattern(pp, "org/*/user")
+	if err != nil {
+		t.Fatalf("failed to parse pattern: %v", err)
+	}
+	sk, err := KeyGen(pp, msk, pattern)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	otherPattern, err := ParsePattern(pp, "org/*/other")
+	if err != nil {
+		t.Fatalf("failed to parse pattern: %v", err)
+	}
+	otherSK, err := KeyGen(pp, msk, otherPattern)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+
+	tests := []struct {
+		name   string
+		tamper func(ct *HybridCiphertext)
+		sk     *PrivateKey
+	}{
+		{"payload", func(ct *HybridCiphertext) { ct.Payload[len(ct.Payload)-1] ^= 1 }, sk},
+		{"header Y", func(ct *HybridCiphertext) { ct.Header.Y.Double() }, sk},
+		{"header Z", func(ct *HybridCiphertext) { ct.Header.Z.Double() }, sk},
+		{"wrong key", func(ct *HybridCiphertext) {}, otherSK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ct, err := EncryptBytes(pp, pattern, msg)
+			if err != nil {
+				t.Fatalf("EncryptBytes failed: %v", err)
+			}
+			tc.tamper(ct)
+			if _, err := DecryptBytes(pp, tc.sk, ct); err != ErrInvalidPayload {
+				t.Fatalf("expected ErrInvalidPayload, but got %v", err)
+			}
+		})
+	}
+}
@@ -177,6 +177,8 @@ func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
 	return buf, nil
 }
 
+// UnmarshalBinary does not check that the key's Bs match its pattern; see
+// [PrivateKey.Validate].
 func (sk *PrivateKey) UnmarshalBinary(data []byte) error {
 	offset := 0
 
@@ -0,0 +1,174 @@
+package akn07
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// Patterns have a path syntax: the components are separated by '/', and a
+// component of '*' is a free slot (a wildcard), as in "acme/*/eng/alice".  A
+// backslash escapes the next character, so that "\/", "\*" and "\\" are a
+// literal slash, star, and backslash; a component that starts with an
+// unescaped '#' is the hex encoding of a slot's scalar, for slots whose
+// string is unknown.  The path covers only the first MaxDepth-1 slots: the
+// last slot is reserved for signatures (and for [EncryptCCA]), and is free.
+// Slots past the end of the path are free, so "acme" and "acme/*" are the
+// same pattern.
+
+var ErrInvalidPath = errors.New("akn07: invalid pattern path")
+
+const (
+	pathSep      = '/'
+	pathEscape   = '\\'
+	pathWildcard = "*"
+	pathRaw      = '#'
+)
+
+// pathComponent is a parsed component: a free slot, a scalar, or a string.
+type pathComponent struct {
+	free   bool
+	scalar *bls.Scalar
+	text   []byte
+}
+
+func splitPath(path string) ([]pathComponent, error) {
+	var comps []pathComponent
+	var cur []byte
+	escaped := false // whether cur contains an escaped character
+	raw := false     // whether cur started with an unescaped '#'
+
+	finish := func() error {
+		var c pathComponent
+		switch {
+		case !escaped && string(cur) == pathWildcard:
+			c.free = true
+		case raw:
+			b, err := hex.DecodeString(string(cur[1:]))
+			if err != nil {
+				return ErrInvalidPath
+			}
+			c.scalar = new(bls.Scalar)
+			if err := c.scalar.UnmarshalBinary(b); err != nil {
+				return ErrInvalidPath
+			}
+		case len(cur) == 0:
+			return ErrInvalidPath
+		default:
+			c.text = cur
+		}
+		comps = append(comps, c)
+		cur, escaped, raw = nil, false, false
+		return nil
+	}
+
+	for i := 0; i < len(path); i++ {
+		switch ch := path[i]; {
+		case ch == pathEscape:
+			i++
+			if i == len(path) {
+				return nil, ErrInvalidPath
+			}
+			cur = append(cur, path[i])
+			escaped = true
+		case ch == pathSep:
+			if err := finish(); err != nil {
+				return nil, err
+			}
+		default:
+			if ch == pathRaw && len(cur) == 0 {
+				raw = true
+			}
+			cur = append(cur, ch)
+		}
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+
+	return comps, nil
+}
+
+// ParsePattern parses a path such as "acme/*/eng/alice" into a pattern.  The
+// path may have at most pp.MaxDepth-1 components.
+func ParsePattern(pp *PublicParams, path string) (*Pattern, error) {
+	comps, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(comps) > pp.MaxDepth-1 {
+		return nil, ErrPatternExceedsMaxDepth
+	}
+
+	components := make([][]byte, pp.MaxDepth)
+	for i, c := range comps {
+		components[i] = c.text
+	}
+	pattern, err := NewPattern(pp, components)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range comps {
+		if c.scalar != nil {
+			pattern.Ps[i] = c.scalar
+		}
+	}
+
+	return pattern, nil
+}
+
+func escapeComponent(c []byte) string {
+	if string(c) == pathWildcard {
+		return `\*`
+	}
+	var sb strings.Builder
+	for i, ch := range c {
+		if ch == pathSep || ch == pathEscape || (ch == pathRaw && i == 0) {
+			sb.WriteByte(pathEscape)
+		}
+		sb.WriteByte(ch)
+	}
+	return sb.String()
+}
+
+// String formats the pattern in the path syntax, with trailing free slots
+// omitted.  A fixed slot whose string is unknown is formatted as its scalar.
+// The last slot is not shown, so the string of a pattern that fixes it does
+// not parse back to the same pattern.
+func (p *Pattern) String() string {
+	n := len(p.Ps) - 1
+	for n > 0 && p.Ps[n-1] == nil {
+		n--
+	}
+	if n <= 0 {
+		return pathWildcard
+	}
+
+	parts := make([]string, n)
+	for i := range parts {
+		switch {
+		case p.Ps[i] == nil:
+			parts[i] = pathWildcard
+		case i < len(p.Components) && p.Components[i] != nil:
+			parts[i] = escapeComponent(p.Components[i])
+		default:
+			parts[i] = string(pathRaw) + hex.EncodeToString(blspairing.ScalarToBytes(p.Ps[i]))
+		}
+	}
+	return strings.Join(parts, string(pathSep))
+}
+
+// Derivable returns the candidates for which [KeyDer] can derive a key from
+// sk: those that fix every slot that sk's pattern fixes, to the same value.
+func Derivable(sk *PrivateKey, candidates []*Pattern) []*Pattern {
+	var out []*Pattern
+	for _, c := range candidates {
+		if c.Depth() == sk.Pattern.Depth() && sk.Pattern.Matches(c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
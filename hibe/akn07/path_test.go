@@ -0,0 +1,141 @@
+package akn07
+
+import (
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	pp, _ := Setup(DefaultDepth)
+
+	tests := []struct {
+		path       string
+		components []string
+		want       string
+	}{
+		{"acme/*/eng/alice", []string{"acme", "", "eng", "alice"}, "acme/*/eng/alice"},
+		{"acme/*/*", []string{"acme"}, "acme"},
+		{"*", nil, "*"},
+		{`a\/b/c`, []string{"a/b", "c"}, `a\/b/c`},
+		{`\*/x`, []string{"*", "x"}, `\*/x`},
+		{`a\\/b`, []string{`a\`, "b"}, `a\\/b`},
+		{`\#tag`, []string{"#tag"}, `\#tag`},
+		{`a#b`, []string{"a#b"}, `a#b`},
+		{`\x\y`, []string{"xy"}, "xy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParsePattern(pp, tt.path)
+			if err != nil {
+				t.Fatalf("failed to parse pattern: %v", err)
+			}
+
+			want, err := NewPatternFromStrings(pp, tt.components)
+			if err != nil {
+				t.Fatalf("failed to create pattern: %v", err)
+			}
+			if !got.Matches(want) || !want.Matches(got) {
+				t.Fatalf("parsed pattern differs from NewPatternFromStrings(%q)", tt.components)
+			}
+
+			if s := got.String(); s != tt.want {
+				t.Fatalf("expected String() to be %q, but got %q", tt.want, s)
+			}
+		})
+	}
+}
+
+func TestParsePattern_invalid(t *testing.T) {
+	pp, _ := Setup(4)
+
+	tests := []struct {
+		path string
+		want error
+	}{
+		{"", ErrInvalidPath},
+		{"a//b", ErrInvalidPath},
+		{"a/", ErrInvalidPath},
+		{`a\`, ErrInvalidPath},
+		{"#zz", ErrInvalidPath},
+		{"#00", ErrInvalidPath},
+		// the fourth slot is reserved for signatures
+		{"a/b/c/d", ErrPatternExceedsMaxDepth},
+	}
+
+	for _, tt := range tests {
+		if _, err := ParsePattern(pp, tt.path); err != tt.want {
+			t.Fatalf("%q: expected %v, but got %v", tt.path, tt.want, err)
+		}
+	}
+}
+
+// A pattern whose strings are lost still round-trips through String.
+func TestPatternString_unknownComponents(t *testing.T) {
+	pp, _ := Setup(DefaultDepth)
+
+	original, err := ParsePattern(pp, "acme/*/eng")
+	if err != nil {
+		t.Fatalf("failed to parse pattern: %v", err)
+	}
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	deserialized := new(Pattern)
+	if err := deserialized.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+
+	s := deserialized.String()
+	if s[0] != '#' {
+		t.Fatalf("expected a raw component, but got %q", s)
+	}
+	reparsed, err := ParsePattern(pp, s)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", s, err)
+	}
+	if !reparsed.Matches(original) || !original.Matches(reparsed) {
+		t.Fatalf("%q does not parse back to the original pattern", s)
+	}
+}
+
+func TestDerivable(t *testing.T) {
+	pp, msk := Setup(DefaultDepth)
+
+	parent, err := ParsePattern(pp, "acme/*/eng")
+	if err != nil {
+		t.Fatalf("failed to parse pattern: %v", err)
+	}
+	sk, err := KeyGen(pp, msk, parent)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	paths := []string{"acme/*/eng", "acme/nyc/eng", "acme/nyc/eng/alice", "acme/*/eng/*/x", "acme/nyc/ops", "acme", "other/*/eng"}
+	var candidates []*Pattern
+	for _, path := range paths {
+		p, err := ParsePattern(pp, path)
+		if err != nil {
+			t.Fatalf("failed to parse pattern: %v", err)
+		}
+		candidates = append(candidates, p)
+	}
+
+	var got []string
+	for _, p := range Derivable(sk, candidates) {
+		got = append(got, p.String())
+		if _, err := KeyDer(pp, sk, p); err != nil {
+			t.Fatalf("KeyDer failed for derivable pattern %s: %v", p, err)
+		}
+	}
+
+	want := []string{"acme/*/eng", "acme/nyc/eng", "acme/nyc/eng/alice", "acme/*/eng/*/x"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %q, but got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %q, but got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package akn07
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+var ErrInvalidSignatureEncoding = errors.New("akn07: invalid signature encoding")
+
+var msgDomainSepTag = []byte("akn07-sign-msg")
+
+// HashMessage hashes a message to the scalar that [SignBytes] signs.  The
+// hash is domain-separated from the hashes of [EncryptCCA]'s verification
+// keys, so that a signature on a message is never a decryption key.
+func HashMessage(msg []byte) *bls.Scalar {
+	buf := make([]byte, 0, len(msgDomainSepTag)+len(msg))
+	buf = append(buf, msgDomainSepTag...)
+	buf = append(buf, msg...)
+	return blspairing.HashBytesToScalar(buf)
+}
+
+// SignBytes signs msg, which it hashes with [HashMessage].  As with [Sign],
+// the key's pattern must leave the last slot free.
+func SignBytes(pp *PublicParams, sk *PrivateKey, msg []byte) *Signature {
+	return Sign(pp, sk, HashMessage(msg))
+}
+
+// VerifyBytes verifies a signature from [SignBytes].  It rejects signer
+// patterns that fix the last slot: the message's hash is added to that
+// slot's component, so such a pattern would let a signature on one message
+// verify for any other.
+func VerifyBytes(pp *PublicParams, signerPattern *Pattern, sig *Signature, msg []byte) bool {
+	if !lastSlotFree(signerPattern) {
+		return false
+	}
+	return Verify(pp, signerPattern, sig, HashMessage(msg))
+}
+
+// PatternSignature is a signature that carries the signer's pattern, so that
+// it can be verified without any other context than the public parameters.
+// SignerPattern is the pattern's canonical encoding (see
+// [Pattern.MarshalBinary]).
+type PatternSignature struct {
+	SignerPattern []byte
+	Sig           *Signature
+}
+
+// SignWithPattern signs msg as [SignBytes] does, and attaches the key's
+// pattern.
+func SignWithPattern(pp *PublicParams, sk *PrivateKey, msg []byte) (*PatternSignature, error) {
+	if !lastSlotFree(sk.Pattern) {
+		return nil, ErrLastSlotFixed
+	}
+
+	p, err := sk.Pattern.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PatternSignature{
+		SignerPattern: p,
+		Sig:           SignBytes(pp, sk, msg),
+	}, nil
+}
+
+// decodePattern decodes a pattern, and rejects encodings that are not
+// canonical and patterns that fix the last slot.
+func decodePattern(pp *PublicParams, data []byte) (*Pattern, error) {
+	p := new(Pattern)
+	if err := p.UnmarshalBinary(data); err != nil {
+		return nil, ErrInvalidSignatureEncoding
+	}
+	if p.Depth() != pp.MaxDepth {
+		return nil, ErrPatternInvalidDepth
+	}
+	if !lastSlotFree(p) {
+		return nil, ErrLastSlotFixed
+	}
+	canonical, err := p.MarshalBinary()
+	if err != nil || !bytes.Equal(canonical, data) {
+		return nil, ErrInvalidSignatureEncoding
+	}
+	return p, nil
+}
+
+// Verify checks the signature on msg against the attached pattern, and
+// returns the pattern.  The caller must still decide whether the signer's
+// pattern is authorized to sign msg.
+func (ps *PatternSignature) Verify(pp *PublicParams, msg []byte) (*Pattern, error) {
+	p, err := decodePattern(pp, ps.SignerPattern)
+	if err != nil {
+		return nil, err
+	}
+	if ps.Sig == nil || !VerifyBytes(pp, p, ps.Sig, msg) {
+		return nil, ErrInvalidSignature
+	}
+	return p, nil
+}
+
+func (ps *PatternSignature) MarshalBinary() ([]byte, error) {
+	sigBytes, err := ps.Sig.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 4, 4+len(ps.SignerPattern)+len(sigBytes))
+	binary.BigEndian.PutUint32(buf, uint32(len(ps.SignerPattern)))
+	buf = append(buf, ps.SignerPattern...)
+	buf = append(buf, sigBytes...)
+
+	return buf, nil
+}
+
+func (ps *PatternSignature) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("invalid pattern signature data: too short")
+	}
+
+	patternLen := int(binary.BigEndian.Uint32(data[:4]))
+	offset := 4
+
+	if len(data) != offset+patternLen+g1Size+g2Size {
+		return errors.New("invalid pattern signature data: wrong length")
+	}
+
+	sig := new(Signature)
+	if err := sig.UnmarshalBinary(data[offset+patternLen:]); err != nil {
+		return err
+	}
+
+	ps.SignerPattern = bytes.Clone(data[offset : offset+patternLen])
+	ps.Sig = sig
+	return nil
+}
@@ -0,0 +1,130 @@
+package akn07
+
+import (
+	"testing"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestSignBytes(t *testing.T) {
+	pp, msk := Setup(DefaultDepth)
+
+	pattern, err := ParsePattern(pp, "com/example/alice")
+	if err != nil {
+		t.Fatalf("failed to parse pattern: %v", err)
+	}
+	sk, err := KeyGen(pp, msk, pattern)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	sig := SignBytes(pp, sk, msg)
+	if !VerifyBytes(pp, pattern, sig, msg) {
+		t.Fatalf("signature did not verify")
+	}
+	if VerifyBytes(pp, pattern, sig, []byte("another message")) {
+		t.Fatalf("signature verified for another message")
+	}
+
+	// SignBytes does not sign the plain hash of the message
+	if Verify(pp, pattern, sig, blspairing.HashBytesToScalar(msg)) {
+		t.Fatalf("message hash is not domain-separated")
+	}
+
+	bob, err := ParsePattern(pp, "com/example/bob")
+	if err != nil {
+		t.Fatalf("failed to parse pattern: %v", err)
+	}
+	if VerifyBytes(pp, bob, sig, msg) {
+		t.Fatalf("signature verified for another signer")
+	}
+}
+
+func TestPatternSignature(t *testing.T) {
+	pp, msk := Setup(DefaultDepth)
+
+	pattern, err := ParsePattern(pp, "com/*/alice")
+	if err != nil {
+		t.Fatalf("failed to parse pattern: %v", err)
+	}
+	sk, err := KeyGen(pp, msk, pattern)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	ps, err := SignWithPattern(pp, sk, msg)
+	if err != nil {
+		t.Fatalf("SignWithPattern failed: %v", err)
+	}
+
+	blob, err := ps.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	got := new(PatternSignature)
+	if err := got.UnmarshalBinary(blob); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	signer, err := got.Verify(pp, msg)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !signer.Matches(pattern) || !pattern.Matches(signer) {
+		t.Fatalf("Verify returned the wrong signer pattern")
+	}
+
+	if _, err := got.Verify(pp, []byte("another message")); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature, but got %v", err)
+	}
+
+	// claim to be another signer
+	bob, err := ParsePattern(pp, "com/*/bob")
+	if err != nil {
+		t.Fatalf("failed to parse pattern: %v", err)
+	}
+	forged := *got
+	forged.SignerPattern, err = bob.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	if _, err := forged.Verify(pp, msg); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature, but got %v", err)
+	}
+
+	// fix the last slot to H(msg) - H(other), so that the signature on msg
+	// would also verify for other
+	other := []byte("another message")
+	lastFixed := pattern.Clone()
+	last := new(bls.Scalar)
+	last.Sub(HashMessage(msg), HashMessage(other))
+	lastFixed.Ps[len(lastFixed.Ps)-1] = last
+	if VerifyBytes(pp, lastFixed, got.Sig, other) {
+		t.Fatalf("VerifyBytes accepted a signer pattern that fixes the last slot")
+	}
+	forged = *got
+	forged.SignerPattern, err = lastFixed.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	if _, err := forged.Verify(pp, other); err != ErrLastSlotFixed {
+		t.Fatalf("expected ErrLastSlotFixed, but got %v", err)
+	}
+
+	// a non-canonical encoding of the same pattern: a free slot's flag
+	// other than 0
+	nonCanonical := *got
+	nonCanonical.SignerPattern = append([]byte(nil), got.SignerPattern...)
+	nonCanonical.SignerPattern[4+1+32] = 2
+	if _, err := nonCanonical.Verify(pp, msg); err != ErrInvalidSignatureEncoding {
+		t.Fatalf("expected ErrInvalidSignatureEncoding, but got %v", err)
+	}
+
+	if err := new(PatternSignature).UnmarshalBinary(blob[:len(blob)-1]); err == nil {
+		t.Fatalf("UnmarshalBinary accepted truncated data")
+	}
+}
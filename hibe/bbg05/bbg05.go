@@ -2,6 +2,7 @@ package bbg05
 
 import (
 	"errors"
+	"slices"
 
 	bls "github.com/cloudflare/circl/ecc/bls12381"
 	"github.com/etclab/ncircl/util/blspairing"
@@ -10,6 +11,7 @@ import (
 var (
 	ErrIdExceedsMaxDepth = errors.New("Id exceeds maximum depth")
 	ErrIdNotChild        = errors.New("Id is not a valid child of parent Id")
+	ErrInvalidPrivateKey = errors.New("bbg05: invalid private key")
 )
 
 type PublicParams struct {
@@ -82,6 +84,14 @@ func NewIdFromStrings(pp *PublicParams, components []string) (*Id, error) {
 	return NewId(pp, byteComponents)
 }
 
+func (id *Id) Clone() *Id {
+	is := make([]*bls.Scalar, len(id.Is))
+	for i, x := range id.Is {
+		is[i] = blspairing.CloneScalar(x)
+	}
+	return &Id{Is: is}
+}
+
 func (id *Id) Depth() int {
 	return len(id.Is)
 }
@@ -153,11 +163,46 @@ func KeyGen(pp *PublicParams, msk *MasterKey, id *Id) (*PrivateKey, error) {
 		A0: A0,
 		A1: A1,
 		Bs: Bs,
-		Id: id, /* TODO: clone this? */
+		Id: id.Clone(),
 	}, nil
 }
 
+func (sk *PrivateKey) Clone() *PrivateKey {
+	bs := make([]*bls.G2, len(sk.Bs))
+	for i, b := range sk.Bs {
+		bs[i] = blspairing.CloneG2(b)
+	}
+
+	return &PrivateKey{
+		A0: blspairing.CloneG2(sk.A0),
+		A1: blspairing.CloneG1(sk.A1),
+		Bs: bs,
+		Id: sk.Id.Clone(),
+	}
+}
+
+// Validate checks that the key has the shape of a key for its identity: one
+// B for each level below the identity.  Keys from [KeyGen] and [KeyDer] are
+// always valid; keys from [PrivateKey.UnmarshalBinary] are not checked
+// against the public parameters, and should be validated before they are
+// used.
+func (sk *PrivateKey) Validate(pp *PublicParams) error {
+	if sk.A0 == nil || sk.A1 == nil || sk.Id == nil {
+		return ErrInvalidPrivateKey
+	}
+	if sk.Id.Depth() > pp.MaxDepth || len(sk.Bs) != pp.MaxDepth-sk.Id.Depth() {
+		return ErrInvalidPrivateKey
+	}
+	if slices.Contains(sk.Bs, nil) || slices.Contains(sk.Id.Is, nil) {
+		return ErrInvalidPrivateKey
+	}
+	return nil
+}
+
 func KeyDer(pp *PublicParams, parentSk *PrivateKey, childId *Id) (*PrivateKey, error) {
+	if err := parentSk.Validate(pp); err != nil {
+		return nil, err
+	}
 	if childId.Depth() > pp.MaxDepth {
 		return nil, ErrIdExceedsMaxDepth
 	}
@@ -182,13 +227,14 @@ func KeyDer(pp *PublicParams, parentSk *PrivateKey, childId *Id) (*PrivateKey, e
 	A0 := agg
 
 	A1 := new(bls.G1)
-	A1.ScalarMult(t, parentSk.A1)
+	A1.ScalarMult(t, pp.G)
+	A1.Add(A1, parentSk.A1)
 
 	Bs := make([]*bls.G2, 0, len(pp.Hs)-len(childId.Is))
 	for i := len(childId.Is); i < len(pp.Hs); i++ {
 		b := new(bls.G2)
 		b.ScalarMult(t, pp.Hs[i])
-		b.Add(b, parentSk.Bs[i+1])
+		b.Add(b, parentSk.Bs[i-k])
 		Bs = append(Bs, b)
 	}
 
@@ -196,10 +242,51 @@ func KeyDer(pp *PublicParams, parentSk *PrivateKey, childId *Id) (*PrivateKey, e
 		A0: A0,
 		A1: A1,
 		Bs: Bs,
-		Id: childId, /* TODO: clone this? */
+		Id: childId.Clone(),
 	}, nil
 }
 
+// idHash returns h_1^{I_1} ... h_k^{I_k} g_3.
+func idHash(pp *PublicParams, id *Id) *bls.G2 {
+	agg := blspairing.NewG2Identity()
+	var tmp bls.G2
+	for i := 0; i < len(id.Is); i++ {
+		tmp.ScalarMult(id.Is[i], pp.Hs[i])
+		agg.Add(agg, &tmp)
+	}
+	agg.Add(agg, pp.G3)
+	return agg
+}
+
+// Rerandomize returns a copy of sk with fresh randomness: the key for the
+// same identity, with r replaced by r + t for a random t.  The result has the
+// same distribution as a key from [KeyGen].  It returns
+// [ErrInvalidPrivateKey] if sk fails [PrivateKey.Validate].
+func Rerandomize(pp *PublicParams, sk *PrivateKey) (*PrivateKey, error) {
+	if err := sk.Validate(pp); err != nil {
+		return nil, err
+	}
+
+	t := blspairing.NewRandomScalar()
+	out := sk.Clone()
+
+	tmp := idHash(pp, sk.Id)
+	tmp.ScalarMult(t, tmp)
+	out.A0.Add(out.A0, tmp)
+
+	var tmp1 bls.G1
+	tmp1.ScalarMult(t, pp.G)
+	out.A1.Add(out.A1, &tmp1)
+
+	k := sk.Id.Depth()
+	for j, b := range out.Bs {
+		tmp.ScalarMult(t, pp.Hs[k+j])
+		b.Add(b, tmp)
+	}
+
+	return out, nil
+}
+
 type Ciphertext struct {
 	A *bls.Gt
 	B *bls.G1
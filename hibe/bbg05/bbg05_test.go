@@ -0,0 +1,419 @@
+package bbg05
+
+import (
+	"testing"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestKeyDer(t *testing.T) {
+	pp, msk := Setup(4)
+	comps := []string{"com", "example", "alice", "laptop"}
+
+	id, err := NewIdFromStrings(pp, nil)
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	sk, err := KeyGen(pp, msk, id)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	for d := 1; d <= len(comps); d++ {
+		id, err = NewIdFromStrings(pp, comps[:d])
+		if err != nil {
+			t.Fatalf("NewIdFromStrings failed: %v", err)
+		}
+		sk, err = KeyDer(pp, sk, id)
+		if err != nil {
+			t.Fatalf("KeyDer failed at depth %d: %v", d, err)
+		}
+		if len(sk.Bs) != pp.MaxDepth-d {
+			t.Fatalf("expected %d Bs at depth %d, but got %d", pp.MaxDepth-d, d, len(sk.Bs))
+		}
+
+		m := blspairing.NewRandomGt()
+		ct, err := Encrypt(pp, id, m)
+		if err != nil {
+			t.Fatalf("Encrypt failed: %v", err)
+		}
+		if !Decrypt(pp, sk, ct).IsEqual(m) {
+			t.Fatalf("decryption with a derived key failed at depth %d", d)
+		}
+	}
+}
+
+func TestPublicParamsSerialization(t *testing.T) {
+	original, _ := Setup(5)
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	deserialized := new(PublicParams)
+	if err := deserialized.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+
+	if deserialized.MaxDepth != original.MaxDepth {
+		t.Fatalf("MaxDepth mismatch: got %d, want %d", deserialized.MaxDepth, original.MaxDepth)
+	}
+	if !deserialized.G.IsEqual(original.G) {
+		t.Fatalf("G mismatch")
+	}
+	if !deserialized.G1.IsEqual(original.G1) {
+		t.Fatalf("G1 mismatch")
+	}
+	if !deserialized.G2.IsEqual(original.G2) {
+		t.Fatalf("G2 mismatch")
+	}
+	if !deserialized.G3.IsEqual(original.G3) {
+		t.Fatalf("G3 mismatch")
+	}
+	for i := range original.Hs {
+		if !deserialized.Hs[i].IsEqual(original.Hs[i]) {
+			t.Fatalf("Hs[%d] mismatch", i)
+		}
+	}
+
+	if err := new(PublicParams).UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatalf("UnmarshalBinary accepted truncated data")
+	}
+}
+
+func TestMasterKeySerialization(t *testing.T) {
+	_, original := Setup(5)
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	deserialized := new(MasterKey)
+	if err := deserialized.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+
+	if !deserialized.G2toA.IsEqual(original.G2toA) {
+		t.Fatalf("G2toA mismatch")
+	}
+}
+
+func TestIdSerialization(t *testing.T) {
+	pp, _ := Setup(5)
+
+	for _, comps := range [][]string{nil, {"com"}, {"com", "example", "alice"}} {
+		original, err := NewIdFromStrings(pp, comps)
+		if err != nil {
+			t.Fatalf("NewIdFromStrings failed: %v", err)
+		}
+
+		data, err := original.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary failed: %v", err)
+		}
+
+		deserialized := new(Id)
+		if err := deserialized.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+
+		if deserialized.Depth() != original.Depth() {
+			t.Fatalf("depth mismatch: got %d, want %d", deserialized.Depth(), original.Depth())
+		}
+		for i := range original.Is {
+			if original.Is[i].IsEqual(deserialized.Is[i]) == 0 {
+				t.Fatalf("scalar mismatch at index %d", i)
+			}
+		}
+	}
+}
+
+// A delegated key sent over the wire can be delegated further.
+func TestPrivateKeySerialization(t *testing.T) {
+	pp, msk := Setup(5)
+
+	parentId, err := NewIdFromStrings(pp, []string{"com", "example"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	original, err := KeyGen(pp, msk, parentId)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	deserialized := new(PrivateKey)
+	if err := deserialized.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+
+	if !deserialized.A0.IsEqual(original.A0) {
+		t.Fatalf("A0 mismatch")
+	}
+	if !deserialized.A1.IsEqual(original.A1) {
+		t.Fatalf("A1 mismatch")
+	}
+	if len(deserialized.Bs) != len(original.Bs) {
+		t.Fatalf("Bs length mismatch: got %d, want %d", len(deserialized.Bs), len(original.Bs))
+	}
+	for i := range original.Bs {
+		if !deserialized.Bs[i].IsEqual(original.Bs[i]) {
+			t.Fatalf("Bs[%d] mismatch", i)
+		}
+	}
+
+	childId, err := NewIdFromStrings(pp, []string{"com", "example", "alice"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	childKey, err := KeyDer(pp, deserialized, childId)
+	if err != nil {
+		t.Fatalf("KeyDer failed: %v", err)
+	}
+
+	m := blspairing.NewRandomGt()
+	ct, err := Encrypt(pp, childId, m)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !Decrypt(pp, childKey, ct).IsEqual(m) {
+		t.Fatalf("decryption with a key derived from the deserialized key failed")
+	}
+
+	if err := new(PrivateKey).UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatalf("UnmarshalBinary accepted truncated data")
+	}
+}
+
+// A decoded key whose Bs do not match its identity is rejected rather than
+// causing a panic.
+func TestPrivateKeyWrongLength(t *testing.T) {
+	pp, msk := Setup(5)
+
+	id, err := NewIdFromStrings(pp, []string{"com", "example"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	sk, err := KeyGen(pp, msk, id)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+	if err := sk.Validate(pp); err != nil {
+		t.Fatalf("Validate rejected a valid key: %v", err)
+	}
+
+	for _, n := range []int{0, 1, len(sk.Bs) - 1, len(sk.Bs) + 1} {
+		bad := sk.Clone()
+		if n <= len(bad.Bs) {
+			bad.Bs = bad.Bs[:n]
+		} else {
+			bad.Bs = append(bad.Bs, blspairing.NewRandomG2())
+		}
+		data, err := bad.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary failed: %v", err)
+		}
+
+		decoded := new(PrivateKey)
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+		if err := decoded.Validate(pp); err != ErrInvalidPrivateKey {
+			t.Fatalf("%d Bs: expected ErrInvalidPrivateKey from Validate, but got %v", n, err)
+		}
+
+		childId, err := NewIdFromStrings(pp, []string{"com", "example", "alice"})
+		if err != nil {
+			t.Fatalf("NewIdFromStrings failed: %v", err)
+		}
+		if _, err := KeyDer(pp, decoded, childId); err != ErrInvalidPrivateKey {
+			t.Fatalf("%d Bs: expected ErrInvalidPrivateKey from KeyDer, but got %v", n, err)
+		}
+
+		ct, err := EncryptCCA(pp, id, blspairing.NewRandomGt())
+		if err != nil {
+			t.Fatalf("EncryptCCA failed: %v", err)
+		}
+		if _, err := DecryptCCA(pp, decoded, ct); err != ErrInvalidPrivateKey {
+			t.Fatalf("%d Bs: expected ErrInvalidPrivateKey from DecryptCCA, but got %v", n, err)
+		}
+		if _, err := Rerandomize(pp, decoded); err != ErrInvalidPrivateKey {
+			t.Fatalf("%d Bs: expected ErrInvalidPrivateKey from Rerandomize, but got %v", n, err)
+		}
+	}
+}
+
+func TestCiphertextSerialization(t *testing.T) {
+	pp, _ := Setup(5)
+
+	id, err := NewIdFromStrings(pp, []string{"com", "example", "alice"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	original, err := Encrypt(pp, id, blspairing.NewRandomGt())
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	deserialized := new(Ciphertext)
+	if err := deserialized.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+
+	if !deserialized.A.IsEqual(original.A) {
+		t.Fatalf("A mismatch")
+	}
+	if !deserialized.B.IsEqual(original.B) {
+		t.Fatalf("B mismatch")
+	}
+	if !deserialized.C.IsEqual(original.C) {
+		t.Fatalf("C mismatch")
+	}
+
+	if err := new(Ciphertext).UnmarshalBinary(append(data, 0)); err == nil {
+		t.Fatalf("UnmarshalBinary accepted trailing data")
+	}
+}
+
+// wellFormed checks that sk has the form of a KeyGen key for its id, for
+// some r: A1 = r*g, A0 = g_2^alpha * (h_1^{I_1} ... h_k^{I_k} g_3)^r, and
+// B_j = h_j^r.
+func wellFormed(pp *PublicParams, msk *MasterKey, sk *PrivateKey) bool {
+	if len(sk.Bs) != pp.MaxDepth-sk.Id.Depth() {
+		return false
+	}
+
+	a0 := new(bls.G2)
+	a0.Add(sk.A0, negG2(msk.G2toA))
+	if !bls.Pair(pp.G, a0).IsEqual(bls.Pair(sk.A1, idHash(pp, sk.Id))) {
+		return false
+	}
+
+	for j, b := range sk.Bs {
+		if !bls.Pair(pp.G, b).IsEqual(bls.Pair(sk.A1, pp.Hs[sk.Id.Depth()+j])) {
+			return false
+		}
+	}
+	return true
+}
+
+func negG2(g *bls.G2) *bls.G2 {
+	n := blspairing.CloneG2(g)
+	n.Neg()
+	return n
+}
+
+// A key from KeyDer or Rerandomize must have the distribution of a key from
+// KeyGen: it must be well-formed, and its randomness r must be fresh and
+// independent of the parent's.  Since KeyDer and Rerandomize add a fresh
+// uniform t to the parent's r, the second property holds if r changes; the
+// test checks both.
+func TestKeyDistribution(t *testing.T) {
+	pp, msk := Setup(4)
+
+	parentId, err := NewIdFromStrings(pp, []string{"com", "example"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	childId, err := NewIdFromStrings(pp, []string{"com", "example", "alice"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+
+	parent, err := KeyGen(pp, msk, parentId)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+	generated, err := KeyGen(pp, msk, childId)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+	derived, err := KeyDer(pp, parent, childId)
+	if err != nil {
+		t.Fatalf("KeyDer failed: %v", err)
+	}
+	derived2, err := KeyDer(pp, parent, childId)
+	if err != nil {
+		t.Fatalf("KeyDer failed: %v", err)
+	}
+	rerandomized, err := Rerandomize(pp, derived)
+	if err != nil {
+		t.Fatalf("Rerandomize failed: %v", err)
+	}
+
+	keys := map[string]*PrivateKey{
+		"parent":       parent,
+		"generated":    generated,
+		"derived":      derived,
+		"derived2":     derived2,
+		"rerandomized": rerandomized,
+	}
+	for name, sk := range keys {
+		if !wellFormed(pp, msk, sk) {
+			t.Fatalf("%s key is not well-formed", name)
+		}
+	}
+
+	bad := derived.Clone()
+	bad.A1.Double()
+	if wellFormed(pp, msk, bad) {
+		t.Fatalf("a malformed key passed the well-formedness check")
+	}
+
+	// no two keys share randomness
+	for name1, sk1 := range keys {
+		for name2, sk2 := range keys {
+			if name1 < name2 && sk1.A1.IsEqual(sk2.A1) {
+				t.Fatalf("%s and %s keys share randomness", name1, name2)
+			}
+		}
+	}
+
+	m := blspairing.NewRandomGt()
+	ct, err := Encrypt(pp, childId, m)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !Decrypt(pp, rerandomized, ct).IsEqual(m) {
+		t.Fatalf("decryption with a rerandomized key failed")
+	}
+}
+
+// Keys do not alias the ids they were created from, or each other.
+func TestKeyDeepCopy(t *testing.T) {
+	pp, msk := Setup(3)
+
+	id, err := NewIdFromStrings(pp, []string{"com", "example"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	sk, err := KeyGen(pp, msk, id)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+	want := blspairing.CloneScalar(id.Is[0])
+	id.Is[0].SetUint64(7)
+	if sk.Id.Is[0].IsEqual(want) == 0 {
+		t.Fatalf("KeyGen key aliases its id")
+	}
+
+	clone := sk.Clone()
+	clone.A0.Double()
+	clone.Bs[0].Double()
+	clone.Id.Is[1].SetUint64(7)
+	if !wellFormed(pp, msk, sk) {
+		t.Fatalf("Clone aliases the original key")
+	}
+}
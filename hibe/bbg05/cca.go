@@ -0,0 +1,121 @@
+package bbg05
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"slices"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/mu"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// The CCA-secure mode applies the Canetti-Halevi-Katz transform (EUROCRYPT
+// 2004): the sender creates a one-time ed25519 key pair, encrypts to the
+// child of the recipient's identity that is named by the verification key,
+// and signs the ciphertext.  The recipient checks the signature, checks that
+// the ciphertext is well-formed for that child, and decrypts with a key for
+// the child that it derives from its own.  The mode therefore needs one
+// level of the hierarchy below the recipient's identity.
+
+var (
+	ErrInvalidSignature  = errors.New("bbg05: invalid signature")
+	ErrInvalidCiphertext = errors.New("bbg05: invalid ciphertext")
+)
+
+var vkDomainSepTag = []byte("bbg05-cca-vk")
+
+// vkComponent hashes a verification key to an identity component.
+func vkComponent(vk ed25519.PublicKey) *bls.Scalar {
+	buf := make([]byte, 0, len(vkDomainSepTag)+len(vk))
+	buf = append(buf, vkDomainSepTag...)
+	buf = append(buf, vk...)
+	return blspairing.HashBytesToScalar(buf)
+}
+
+// vkChild returns the child of id that is named by vk.
+func vkChild(id *Id, vk ed25519.PublicKey) *Id {
+	return &Id{Is: append(slices.Clone(id.Is), vkComponent(vk))}
+}
+
+type CCACiphertext struct {
+	VK  ed25519.PublicKey
+	CT  *Ciphertext
+	Sig []byte
+}
+
+func (ct *CCACiphertext) MessageToSign() []byte {
+	m := make([]byte, 0, 1024)
+	m = append(m, blspairing.GtToBytes(ct.CT.A)...)
+	m = append(m, ct.CT.B.Bytes()...)
+	m = append(m, ct.CT.C.Bytes()...)
+	return m
+}
+
+// Check verifies the signature, and checks that the ciphertext is
+// well-formed for the child of id named by the verification key:
+// e(B, h_1^{I_1} ... h_{k+1}^{I_{k+1}} g_3) = e(g, C).
+func (ct *CCACiphertext) Check(pp *PublicParams, id *Id) error {
+	if len(ct.VK) != ed25519.PublicKeySize || !ed25519.Verify(ct.VK, ct.MessageToSign(), ct.Sig) {
+		return ErrInvalidSignature
+	}
+
+	if id.Depth() >= pp.MaxDepth {
+		return ErrIdExceedsMaxDepth
+	}
+
+	lhs := bls.Pair(ct.CT.B, idHash(pp, vkChild(id, ct.VK)))
+	rhs := bls.Pair(pp.G, ct.CT.C)
+	if !lhs.IsEqual(rhs) {
+		return ErrInvalidCiphertext
+	}
+
+	return nil
+}
+
+// EncryptCCA encrypts m to id with the CCA-secure mode.  The id must be
+// shallower than pp.MaxDepth.
+func EncryptCCA(pp *PublicParams, id *Id, m *bls.Gt) (*CCACiphertext, error) {
+	if id.Depth() >= pp.MaxDepth {
+		return nil, ErrIdExceedsMaxDepth
+	}
+
+	vk, ssk, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		mu.Panicf("ed25519.GenerateKey failed: %v", err)
+	}
+
+	inner, err := Encrypt(pp, vkChild(id, vk), m)
+	if err != nil {
+		return nil, err
+	}
+
+	ct := &CCACiphertext{
+		VK: vk,
+		CT: inner,
+	}
+	ct.Sig = ed25519.Sign(ssk, ct.MessageToSign())
+
+	return ct, nil
+}
+
+// DecryptCCA checks the ciphertext against the key's identity, and returns
+// [ErrInvalidSignature] or [ErrInvalidCiphertext] if the ciphertext was
+// tampered with or was encrypted to another identity.
+func DecryptCCA(pp *PublicParams, sk *PrivateKey, ct *CCACiphertext) (*bls.Gt, error) {
+	if err := sk.Validate(pp); err != nil {
+		return nil, err
+	}
+	if err := ct.Check(pp, sk.Id); err != nil {
+		return nil, err
+	}
+
+	// A0 of the child's key, with t = 0 in KeyDer: the key is used once
+	// and never leaves this function, so fresh randomness buys nothing.
+	a0 := new(bls.G2)
+	a0.ScalarMult(vkComponent(ct.VK), sk.Bs[0])
+	a0.Add(a0, sk.A0)
+
+	return Decrypt(pp, &PrivateKey{A0: a0, A1: sk.A1}, ct.CT), nil
+}
@@ -0,0 +1,108 @@
+package bbg05
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestDecryptCCA(t *testing.T) {
+	pp, msk := Setup(4)
+	aliceId, err := NewIdFromStrings(pp, []string{"com", "example", "alice"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	aliceSK, err := KeyGen(pp, msk, aliceId)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	m := blspairing.NewRandomGt()
+	ct, err := EncryptCCA(pp, aliceId, m)
+	if err != nil {
+		t.Fatalf("EncryptCCA failed: %v", err)
+	}
+
+	got, err := DecryptCCA(pp, aliceSK, ct)
+	if err != nil {
+		t.Fatalf("DecryptCCA failed: %v", err)
+	}
+	if !got.IsEqual(m) {
+		t.Fatal("decryption failed")
+	}
+
+	bobId, err := NewIdFromStrings(pp, []string{"com", "example", "bob"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	bobSK, err := KeyGen(pp, msk, bobId)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+	if _, err := DecryptCCA(pp, bobSK, ct); err != ErrInvalidCiphertext {
+		t.Fatalf("expected ErrInvalidCiphertext for another recipient, but got %v", err)
+	}
+
+	// the CCA mode needs a level below the recipient
+	fullId, err := NewIdFromStrings(pp, []string{"com", "example", "alice", "laptop"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	if _, err := EncryptCCA(pp, fullId, m); err != ErrIdExceedsMaxDepth {
+		t.Fatalf("expected ErrIdExceedsMaxDepth, but got %v", err)
+	}
+}
+
+func TestDecryptCCA_mauled(t *testing.T) {
+	pp, msk := Setup(3)
+	aliceId, err := NewIdFromStrings(pp, []string{"example", "alice"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	aliceSK, err := KeyGen(pp, msk, aliceId)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	// resign replaces the one-time key, as an attacker could
+	resign := func(ct *CCACiphertext) {
+		vk, ssk, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("ed25519.GenerateKey failed: %v", err)
+		}
+		ct.VK = vk
+		ct.Sig = ed25519.Sign(ssk, ct.MessageToSign())
+	}
+
+	tests := []struct {
+		name string
+		maul func(ct *CCACiphertext)
+		want error
+	}{
+		{"A", func(ct *CCACiphertext) { ct.CT.A.Mul(ct.CT.A, blspairing.NewRandomGt()) }, ErrInvalidSignature},
+		{"B", func(ct *CCACiphertext) { ct.CT.B.Double() }, ErrInvalidSignature},
+		{"C", func(ct *CCACiphertext) { ct.CT.C.Double() }, ErrInvalidSignature},
+		{"Sig", func(ct *CCACiphertext) { ct.Sig[0] ^= 1 }, ErrInvalidSignature},
+		{"VK", func(ct *CCACiphertext) { ct.VK = ct.VK[1:] }, ErrInvalidSignature},
+		{"VK+resign", resign, ErrInvalidCiphertext},
+		{"A+resign", func(ct *CCACiphertext) {
+			ct.CT.A.Mul(ct.CT.A, blspairing.NewRandomGt())
+			resign(ct)
+		}, ErrInvalidCiphertext},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ct, err := EncryptCCA(pp, aliceId, blspairing.NewRandomGt())
+			if err != nil {
+				t.Fatalf("EncryptCCA failed: %v", err)
+			}
+			tc.maul(ct)
+			if _, err := DecryptCCA(pp, aliceSK, ct); err != tc.want {
+				t.Fatalf("expected %v, but got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -39,3 +39,31 @@ func Example() {
 	// Output:
 	// decryption succeeded!
 }
+
+// ExampleEncryptBytes shows how to encrypt a file to an identity in the
+// hierarchy.
+func ExampleEncryptBytes() {
+	pp, msk := bbg05.Setup(5)
+
+	userId, err := bbg05.NewIdFromStrings(pp, []string{"org", "dept", "user"})
+	if err != nil {
+		log.Fatalf("failed to create id: %v", err)
+	}
+	userKey, err := bbg05.KeyGen(pp, msk, userId)
+	if err != nil {
+		log.Fatalf("failed to generate key: %v", err)
+	}
+
+	ct, err := bbg05.EncryptBytes(pp, userId, []byte("The quick brown fox jumps over the lazy dog."))
+	if err != nil {
+		log.Fatalf("failed to encrypt: %v", err)
+	}
+
+	got, err := bbg05.DecryptBytes(pp, userKey, ct)
+	if err != nil {
+		log.Fatalf("failed to decrypt: %v", err)
+	}
+	fmt.Println(string(got))
+	// Output:
+	// The quick brown fox jumps over the lazy dog.
+}
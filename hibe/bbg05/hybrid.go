@@ -0,0 +1,57 @@
+package bbg05
+
+import (
+	"errors"
+
+	"github.com/etclab/ncircl/util/aesx"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+var ErrInvalidPayload = errors.New("bbg05: invalid payload")
+
+// HybridCiphertext is a ciphertext for an arbitrary byte payload.  The Header
+// encapsulates a random Gt element from which the AES-256-GCM key for the
+// Payload is derived.
+type HybridCiphertext struct {
+	Header  *Ciphertext
+	Payload []byte
+}
+
+// The payload is bound to all of the header's components.
+func headerAD(ct *Ciphertext) []byte {
+	m := make([]byte, 0, 1024)
+	m = append(m, blspairing.GtToBytes(ct.A)...)
+	m = append(m, ct.B.Bytes()...)
+	m = append(m, ct.C.Bytes()...)
+	return m
+}
+
+func EncryptBytes(pp *PublicParams, id *Id, msg []byte) (*HybridCiphertext, error) {
+	gt := blspairing.NewRandomGt()
+	header, err := Encrypt(pp, id, gt)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := aesx.EncryptGCM(blspairing.KdfGtToAes256(gt), msg, headerAD(header))
+	if err != nil {
+		return nil, err
+	}
+
+	return &HybridCiphertext{
+		Header:  header,
+		Payload: payload,
+	}, nil
+}
+
+// DecryptBytes returns [ErrInvalidPayload] if the payload or header was
+// tampered with, or if sk is not a key for the ciphertext's id.
+func DecryptBytes(pp *PublicParams, sk *PrivateKey, ct *HybridCiphertext) ([]byte, error) {
+	gt := Decrypt(pp, sk, ct.Header)
+
+	msg, err := aesx.DecryptGCM(blspairing.KdfGtToAes256(gt), ct.Payload, headerAD(ct.Header))
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	return msg, nil
+}
@@ -0,0 +1,79 @@
+package bbg05
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptBytesDecryptBytes(t *testing.T) {
+	pp, msk := Setup(4)
+	id, err := NewIdFromStrings(pp, []string{"org", "dept", "user"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	sk, err := KeyGen(pp, msk, id)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	for _, n := range []int{0, 1, 32, 1000} {
+		msg := bytes.Repeat([]byte{'a'}, n)
+		ct, err := EncryptBytes(pp, id, msg)
+		if err != nil {
+			t.Fatalf("EncryptBytes failed: %v", err)
+		}
+		got, err := DecryptBytes(pp, sk, ct)
+		if err != nil {
+			t.Fatalf("DecryptBytes failed: %v", err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Fatalf("DecryptBytes did not produce the original %d-byte message", n)
+		}
+	}
+}
+
+func TestDecryptBytes_tampered(t *testing.T) {
+	pp, msk := Setup(4)
+	id, err := NewIdFromStrings(pp, []string{"org", "dept", "user"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	sk, err := KeyGen(pp, msk, id)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+	otherId, err := NewIdFromStrings(pp, []string{"org", "dept", "other"})
+	if err != nil {
+		t.Fatalf("NewIdFromStrings failed: %v", err)
+	}
+	otherSK, err := KeyGen(pp, msk, otherId)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+
+	tests := []struct {
+		name   string
+		tamper func(ct *HybridCiphertext)
+		sk     *PrivateKey
+	}{
+		{"payload", func(ct *HybridCiphertext) { ct.Payload[len(ct.Payload)-1] ^= 1 }, sk},
+		{"header B", func(ct *HybridCiphertext) { ct.Header.B.Double() }, sk},
+		{"header C", func(ct *HybridCiphertext) { ct.Header.C.Double() }, sk},
+		{"wrong key", func(ct *HybridCiphertext) {}, otherSK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ct, err := EncryptBytes(pp, id, msg)
+			if err != nil {
+				t.Fatalf("EncryptBytes failed: %v", err)
+			}
+			tc.tamper(ct)
+			if _, err := DecryptBytes(pp, tc.sk, ct); err != ErrInvalidPayload {
+				t.Fatalf("expected ErrInvalidPayload, but got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package bbg05
+
+import (
+	"encoding/binary"
+	"errors"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+const (
+	g1Size     = 96  // BLS12-381 G1 uncompressed point size
+	g2Size     = 192 // BLS12-381 G2 uncompressed point size
+	scalarSize = 32
+)
+
+func (mk *MasterKey) MarshalBinary() ([]byte, error) {
+	return mk.G2toA.Bytes(), nil
+}
+
+func (mk *MasterKey) UnmarshalBinary(data []byte) error {
+	if len(data) != g2Size {
+		return errors.New("invalid master key data: wrong length")
+	}
+	mk.G2toA = new(bls.G2)
+	return mk.G2toA.SetBytes(data)
+}
+
+func (pp *PublicParams) MarshalBinary() ([]byte, error) {
+	size := 4 + g1Size*2 + g2Size*(2+pp.MaxDepth)
+	buf := make([]byte, 4, size)
+	binary.BigEndian.PutUint32(buf, uint32(pp.MaxDepth))
+
+	buf = append(buf, pp.G.Bytes()...)
+	buf = append(buf, pp.G1.Bytes()...)
+	buf = append(buf, pp.G2.Bytes()...)
+	buf = append(buf, pp.G3.Bytes()...)
+
+	for i := 0; i < pp.MaxDepth; i++ {
+		buf = append(buf, pp.Hs[i].Bytes()...)
+	}
+
+	return buf, nil
+}
+
+func (pp *PublicParams) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("invalid public params data: too short")
+	}
+
+	maxDepth := int(binary.BigEndian.Uint32(data[:4]))
+	if len(data) != 4+g1Size*2+g2Size*(2+maxDepth) {
+		return errors.New("invalid public params data: wrong length")
+	}
+	pp.MaxDepth = maxDepth
+	offset := 4
+
+	pp.G = new(bls.G1)
+	if err := pp.G.SetBytes(data[offset : offset+g1Size]); err != nil {
+		return err
+	}
+	offset += g1Size
+
+	pp.G1 = new(bls.G1)
+	if err := pp.G1.SetBytes(data[offset : offset+g1Size]); err != nil {
+		return err
+	}
+	offset += g1Size
+
+	pp.G2 = new(bls.G2)
+	if err := pp.G2.SetBytes(data[offset : offset+g2Size]); err != nil {
+		return err
+	}
+	offset += g2Size
+
+	pp.G3 = new(bls.G2)
+	if err := pp.G3.SetBytes(data[offset : offset+g2Size]); err != nil {
+		return err
+	}
+	offset += g2Size
+
+	pp.Hs = make([]*bls.G2, pp.MaxDepth)
+	for i := 0; i < pp.MaxDepth; i++ {
+		pp.Hs[i] = new(bls.G2)
+		if err := pp.Hs[i].SetBytes(data[offset : offset+g2Size]); err != nil {
+			return err
+		}
+		offset += g2Size
+	}
+
+	return nil
+}
+
+func (id *Id) MarshalBinary() ([]byte, error) {
+	depth := len(id.Is)
+	buf := make([]byte, 4, 4+depth*scalarSize)
+	binary.BigEndian.PutUint32(buf, uint32(depth))
+
+	for i := 0; i < depth; i++ {
+		scalarBytes, err := id.Is[i].MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, scalarBytes...)
+	}
+
+	return buf, nil
+}
+
+func (id *Id) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("invalid id data: too short")
+	}
+
+	depth := int(binary.BigEndian.Uint32(data[:4]))
+	if len(data) != 4+depth*scalarSize {
+		return errors.New("invalid id data: wrong length")
+	}
+
+	id.Is = make([]*bls.Scalar, depth)
+	offset := 4
+	for i := 0; i < depth; i++ {
+		id.Is[i] = new(bls.Scalar)
+		if err := id.Is[i].UnmarshalBinary(data[offset : offset+scalarSize]); err != nil {
+			return err
+		}
+		offset += scalarSize
+	}
+
+	return nil
+}
+
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	var buf []byte
+	buf = append(buf, sk.A0.Bytes()...)
+	buf = append(buf, sk.A1.Bytes()...)
+
+	lenBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(lenBuf, uint32(len(sk.Bs)))
+	buf = append(buf, lenBuf...)
+
+	for i := 0; i < len(sk.Bs); i++ {
+		buf = append(buf, sk.Bs[i].Bytes()...)
+	}
+
+	idBytes, err := sk.Id.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, idBytes...)
+
+	return buf, nil
+}
+
+// UnmarshalBinary does not know the public parameters, so it cannot check
+// that the number of Bs matches the key's identity; callers should check the
+// key with [PrivateKey.Validate].
+func (sk *PrivateKey) UnmarshalBinary(data []byte) error {
+	offset := 0
+
+	if offset+g2Size > len(data) {
+		return errors.New("invalid private key data: A0 truncated")
+	}
+	sk.A0 = new(bls.G2)
+	if err := sk.A0.SetBytes(data[offset : offset+g2Size]); err != nil {
+		return err
+	}
+	offset += g2Size
+
+	if offset+g1Size > len(data) {
+		return errors.New("invalid private key data: A1 truncated")
+	}
+	sk.A1 = new(bls.G1)
+	if err := sk.A1.SetBytes(data[offset : offset+g1Size]); err != nil {
+		return err
+	}
+	offset += g1Size
+
+	if offset+4 > len(data) {
+		return errors.New("invalid private key data: Bs length truncated")
+	}
+	bsLen := int(binary.BigEndian.Uint32(data[offset : offset+4]))
+	offset += 4
+
+	if bsLen > (len(data)-offset)/g2Size {
+		return errors.New("invalid private key data: Bs truncated")
+	}
+	sk.Bs = make([]*bls.G2, bsLen)
+	for i := 0; i < bsLen; i++ {
+		sk.Bs[i] = new(bls.G2)
+		if err := sk.Bs[i].SetBytes(data[offset : offset+g2Size]); err != nil {
+			return err
+		}
+		offset += g2Size
+	}
+
+	sk.Id = new(Id)
+	if err := sk.Id.UnmarshalBinary(data[offset:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ct *Ciphertext) MarshalBinary() ([]byte, error) {
+	aBytes, err := ct.A.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	size := 4 + len(aBytes) + g1Size + g2Size
+	buf := make([]byte, 4, size)
+	binary.BigEndian.PutUint32(buf, uint32(len(aBytes)))
+	buf = append(buf, aBytes...)
+	buf = append(buf, ct.B.Bytes()...)
+	buf = append(buf, ct.C.Bytes()...)
+
+	return buf, nil
+}
+
+func (ct *Ciphertext) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("invalid ciphertext data: too short")
+	}
+
+	gtSize := int(binary.BigEndian.Uint32(data[:4]))
+	offset := 4
+
+	if len(data) != 4+gtSize+g1Size+g2Size {
+		return errors.New("invalid ciphertext data: wrong length")
+	}
+
+	ct.A = new(bls.Gt)
+	if err := ct.A.UnmarshalBinary(data[offset : offset+gtSize]); err != nil {
+		return err
+	}
+	offset += gtSize
+
+	ct.B = new(bls.G1)
+	if err := ct.B.SetBytes(data[offset : offset+g1Size]); err != nil {
+		return err
+	}
+	offset += g1Size
+
+	ct.C = new(bls.G2)
+	if err := ct.C.SetBytes(data[offset : offset+g2Size]); err != nil {
+		return err
+	}
+
+	return nil
+}
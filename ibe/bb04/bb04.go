@@ -0,0 +1,171 @@
+package bb04
+
+import (
+	"crypto/sha256"
+	"io"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/mu"
+	"github.com/etclab/ncircl/util/blspairing"
+	"github.com/etclab/ncircl/util/bytesx"
+	"golang.org/x/crypto/hkdf"
+)
+
+// kdf derives numBytes bytes from a Gt element.
+func kdf(p *bls.Gt, numBytes int) []byte {
+	kdf := hkdf.New(sha256.New, blspairing.GtToBytes(p), nil, nil)
+
+	h := make([]byte, numBytes)
+	_, err := io.ReadFull(kdf, h)
+	if err != nil {
+		mu.Panicf("io.ReadFull failed: %v", err)
+	}
+
+	return h
+}
+
+// IdToScalar maps an identity string to the scalar ID in Z_p.
+func IdToScalar(id []byte) *bls.Scalar {
+	return blspairing.HashBytesToScalar(id)
+}
+
+// PublicParams holds g1 = g^alpha and h in both G1 and G2, and
+// V = e(g1, g2).
+type PublicParams struct {
+	G1ToAlpha *bls.G1
+	G2ToAlpha *bls.G2
+	H1        *bls.G1
+	H2        *bls.G2
+	V         *bls.Gt
+}
+
+type PrivateKeyGenerator struct {
+	PP *PublicParams
+	// master key g2^alpha
+	MK *bls.G1
+}
+
+func NewPrivateKeyGenerator() (*PrivateKeyGenerator, *PublicParams) {
+	alpha := blspairing.NewRandomScalar()
+	beta := blspairing.NewRandomScalar()
+	gamma := blspairing.NewRandomScalar()
+
+	pp := new(PublicParams)
+	pp.G1ToAlpha = new(bls.G1)
+	pp.G1ToAlpha.ScalarMult(alpha, bls.G1Generator())
+	pp.G2ToAlpha = new(bls.G2)
+	pp.G2ToAlpha.ScalarMult(alpha, bls.G2Generator())
+	pp.H1 = new(bls.G1)
+	pp.H1.ScalarMult(gamma, bls.G1Generator())
+	pp.H2 = new(bls.G2)
+	pp.H2.ScalarMult(gamma, bls.G2Generator())
+
+	// g2 = g^beta
+	g2 := new(bls.G2)
+	g2.ScalarMult(beta, bls.G2Generator())
+	pp.V = bls.Pair(pp.G1ToAlpha, g2)
+
+	pkg := new(PrivateKeyGenerator)
+	pkg.PP = pp
+	pkg.MK = new(bls.G1)
+	pkg.MK.ScalarMult(beta, pp.G1ToAlpha)
+
+	return pkg, pp
+}
+
+// idBase1 returns g1^ID * h in G1.
+func idBase1(pp *PublicParams, id []byte) *bls.G1 {
+	f := new(bls.G1)
+	f.ScalarMult(IdToScalar(id), pp.G1ToAlpha)
+	f.Add(f, pp.H1)
+	return f
+}
+
+// idBase2 returns g1^ID * h in G2.
+func idBase2(pp *PublicParams, id []byte) *bls.G2 {
+	f := new(bls.G2)
+	f.ScalarMult(IdToScalar(id), pp.G2ToAlpha)
+	f.Add(f, pp.H2)
+	return f
+}
+
+// PrivateKey is (d0, d1) = (g2^alpha * (g1^ID * h)^r, g^r).
+type PrivateKey struct {
+	D0 *bls.G1
+	D1 *bls.G1
+}
+
+func (pkg *PrivateKeyGenerator) Extract(id []byte) *PrivateKey {
+	r := blspairing.NewRandomScalar()
+
+	d0 := idBase1(pkg.PP, id)
+	d0.ScalarMult(r, d0)
+	d0.Add(d0, pkg.MK)
+
+	d1 := new(bls.G1)
+	d1.ScalarMult(r, bls.G1Generator())
+
+	return &PrivateKey{
+		D0: d0,
+		D1: d1,
+	}
+}
+
+// Rerandomize returns a fresh private key for id, distributed as the output
+// of [PrivateKeyGenerator.Extract], by adding new randomness r' to the key:
+// (d0 * (g1^ID * h)^r', d1 * g^r').  It does not need the master key.
+func (sk *PrivateKey) Rerandomize(pp *PublicParams, id []byte) *PrivateKey {
+	r := blspairing.NewRandomScalar()
+
+	d0 := idBase1(pp, id)
+	d0.ScalarMult(r, d0)
+	d0.Add(d0, sk.D0)
+
+	d1 := new(bls.G1)
+	d1.ScalarMult(r, bls.G1Generator())
+	d1.Add(d1, sk.D1)
+
+	return &PrivateKey{
+		D0: d0,
+		D1: d1,
+	}
+}
+
+// Ciphertext is (B, C, V) = (g^s, (g1^ID * h)^s, M XOR KDF(e(g1, g2)^s)).
+type Ciphertext struct {
+	B *bls.G2
+	C *bls.G2
+	V []byte
+}
+
+func Encrypt(pp *PublicParams, id []byte, msg []byte) *Ciphertext {
+	s := blspairing.NewRandomScalar()
+
+	b := new(bls.G2)
+	b.ScalarMult(s, bls.G2Generator())
+
+	c := idBase2(pp, id)
+	c.ScalarMult(s, c)
+
+	tmp := new(bls.Gt)
+	tmp.Exp(pp.V, s)
+	v := kdf(tmp, len(msg))
+	bytesx.Xor(v, msg)
+
+	return &Ciphertext{
+		B: b,
+		C: c,
+		V: v,
+	}
+}
+
+// Decrypt recovers e(g1, g2)^s as e(d0, B) / e(d1, C).
+func Decrypt(pp *PublicParams, sk *PrivateKey, ct *Ciphertext) []byte {
+	z := bls.Pair(sk.D1, ct.C)
+	z.Inv(z)
+	z.Mul(z, bls.Pair(sk.D0, ct.B))
+
+	msg := kdf(z, len(ct.V))
+	bytesx.Xor(msg, ct.V)
+	return msg
+}
@@ -0,0 +1,74 @@
+package bb04
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecrypt(t *testing.T) {
+	pkg, pp := NewPrivateKeyGenerator()
+	bobSK := pkg.Extract([]byte("bob"))
+
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+	ct := Encrypt(pp, []byte("bob"), msg)
+
+	got := Decrypt(pp, bobSK, ct)
+	if !bytes.Equal(msg, got) {
+		t.Fatalf("expected decrypted message to be %x, but got %x", msg, got)
+	}
+
+	aliceSK := pkg.Extract([]byte("alice"))
+	if got := Decrypt(pp, aliceSK, ct); bytes.Equal(msg, got) {
+		t.Fatal("another identity's key decrypted the message")
+	}
+}
+
+func TestRerandomize(t *testing.T) {
+	pkg, pp := NewPrivateKeyGenerator()
+	id := []byte("bob")
+	sk := pkg.Extract(id)
+	sk2 := sk.Rerandomize(pp, id)
+
+	if sk2.D0.IsEqual(sk.D0) || sk2.D1.IsEqual(sk.D1) {
+		t.Fatal("Rerandomize did not change the key")
+	}
+
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+	got := Decrypt(pp, sk2, Encrypt(pp, id, msg))
+	if !bytes.Equal(msg, got) {
+		t.Fatalf("expected decrypted message to be %x, but got %x", msg, got)
+	}
+}
+
+func BenchmarkExtract(b *testing.B) {
+	pkg, _ := NewPrivateKeyGenerator()
+	id := []byte("test@example.com")
+	for b.Loop() {
+		_ = pkg.Extract(id)
+	}
+}
+
+func BenchmarkEncrypt(b *testing.B) {
+	_, pp := NewPrivateKeyGenerator()
+	id := []byte("test@example.com")
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+	for b.Loop() {
+		_ = Encrypt(pp, id, msg)
+	}
+}
+
+func BenchmarkDecrypt(b *testing.B) {
+	pkg, pp := NewPrivateKeyGenerator()
+	id := []byte("test@example.com")
+	idSK := pkg.Extract(id)
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+	ct := Encrypt(pp, id, msg)
+	for b.Loop() {
+		got := Decrypt(pp, idSK, ct)
+		b.StopTimer()
+		if !bytes.Equal(msg, got) {
+			b.Fatalf("expected decrypted message to be %x, but got %x", msg, got)
+		}
+		b.StartTimer()
+	}
+}
@@ -0,0 +1,29 @@
+// Package bb04 implements the selective-ID secure identity-based encryption
+// scheme BB1 from the [paper]:
+//
+//	@inproceedings{04-eurocrypt-selective_id_ibe,
+//	    title = {Efficient Selective-{ID} Secure Identity-Based Encryption Without Random Oracles},
+//	    author = {Boneh, Dan and Boyen, Xavier},
+//	    booktitle = {International Conference on the Theory and Applications of Cryptographic Techniques (EUROCRYPT)},
+//	    year = {2004},
+//	}
+//
+// Section 4 of that paper describes the scheme.  Unlike [bf01], its security
+// proof does not rely on random oracles.  The API mirrors that of bf01:
+// [NewPrivateKeyGenerator], [PrivateKeyGenerator.Extract], [Encrypt] and
+// [Decrypt].  In addition, anyone can rerandomize a private key (see
+// [PrivateKey.Rerandomize]) without the master key.
+//
+// # Changes from Paper
+// The paper assumes a symmetric pairing.  On BLS12-381, private keys are in
+// G1 (as in bf01) and the ciphertext's group elements are in G2, so the
+// public parameters hold g1 and h in both groups.  An identity is mapped to
+// Z_p with a collision-resistant hash (SHA-256), as the paper suggests for
+// arbitrary identity strings.  To encrypt byte strings, the Gt element
+// e(g1, g2)^s is passed through HKDF and XORed with the message, as bf01 does.
+//
+// # Properties
+//   - CPA-secure under the decisional BDH assumption, for selective identities
+//
+// [paper]: https://eprint.iacr.org/2004/172.pdf
+package bb04
@@ -0,0 +1,110 @@
+package bf01
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/mu"
+	"github.com/etclab/ncircl/util/blspairing"
+	"github.com/etclab/ncircl/util/bytesx"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Both BasicIdent and FullIdent are recipient-anonymous (Boyen and Waters,
+// CRYPTO 2006): U = rP does not depend on the identity, and the rest of the
+// ciphertext is pseudorandom to anyone who cannot compute g_id^r.  A
+// ciphertext therefore reveals nothing about its recipient beyond the length
+// of the message.
+//
+// Anonymity leaves a receiver who holds several keys, or who scans a shared
+// channel, with no way to tell which ciphertexts are addressed to it: BasicIdent
+// decrypts to garbage under the wrong key.  The anonymous mode below adds a
+// key-check tag derived from g_id^r, so that a receiver can test a key with a
+// single pairing.  The tag is as pseudorandom as the rest of the ciphertext,
+// so the mode stays anonymous.  Like BasicIdent, the mode is only CPA-secure:
+// the tag identifies the recipient's key, but does not authenticate the
+// message.
+
+// TagSize is the size in bytes of an [AnonCiphertext]'s key-check tag.
+const TagSize = 16
+
+var ErrNotRecipient = errors.New("bf01: key is not the ciphertext's recipient")
+
+var (
+	anonTagInfo = []byte("bf01-anon-tag")
+	anonMsgInfo = []byte("bf01-anon-msg")
+)
+
+// anonKDF derives the key-check tag and the message mask from g_id^r.
+func anonKDF(z *bls.Gt, numBytes int) ([]byte, []byte) {
+	secret := blspairing.GtToBytes(z)
+
+	tag := make([]byte, TagSize)
+	_, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, anonTagInfo), tag)
+	if err != nil {
+		mu.Panicf("io.ReadFull failed: %v", err)
+	}
+
+	mask := make([]byte, numBytes)
+	_, err = io.ReadFull(hkdf.New(sha256.New, secret, nil, anonMsgInfo), mask)
+	if err != nil {
+		mu.Panicf("io.ReadFull failed: %v", err)
+	}
+
+	return tag, mask
+}
+
+// AnonCiphertext is (U, Tag, V) = (rP, T, M XOR K), where T and K are derived
+// from g_id^r.
+type AnonCiphertext struct {
+	U   *bls.G2
+	Tag []byte
+	V   []byte
+}
+
+func EncryptAnon(pp *PublicParams, id []byte, msg []byte) *AnonCiphertext {
+	r := blspairing.NewRandomScalar()
+	u := new(bls.G2)
+	u.ScalarMult(r, bls.G2Generator())
+
+	pkId := bls.Pair(blspairing.HashBytesToG1(id, nil), pp.MPK)
+	z := new(bls.Gt)
+	z.Exp(pkId, r)
+
+	tag, v := anonKDF(z, len(msg))
+	bytesx.Xor(v, msg)
+
+	return &AnonCiphertext{
+		U:   u,
+		Tag: tag,
+		V:   v,
+	}
+}
+
+// TryDecrypt checks the ciphertext's tag against sk, and returns
+// [ErrNotRecipient] if sk is not the key of the ciphertext's recipient.  The
+// check costs one pairing.
+func TryDecrypt(pp *PublicParams, sk *PrivateKey, ct *AnonCiphertext) ([]byte, error) {
+	tag, msg := anonKDF(bls.Pair(sk.SK, ct.U), len(ct.V))
+	if subtle.ConstantTimeCompare(tag, ct.Tag) != 1 {
+		return nil, ErrNotRecipient
+	}
+	bytesx.Xor(msg, ct.V)
+	return msg, nil
+}
+
+// TrialDecrypt tries each of sks in turn, and returns the index of the first
+// key that is the ciphertext's recipient, along with the message.  It returns
+// [ErrNotRecipient] if none is.
+func TrialDecrypt(pp *PublicParams, sks []*PrivateKey, ct *AnonCiphertext) (int, []byte, error) {
+	for i, sk := range sks {
+		msg, err := TryDecrypt(pp, sk, ct)
+		if err == nil {
+			return i, msg, nil
+		}
+	}
+	return -1, nil, ErrNotRecipient
+}
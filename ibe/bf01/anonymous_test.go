@@ -0,0 +1,99 @@
+package bf01
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestTryDecrypt(t *testing.T) {
+	pkg, pp := NewPrivateKeyGenerator()
+	aliceSK := pkg.Extract([]byte("alice"))
+	bobSK := pkg.Extract([]byte("bob"))
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+
+	ct := EncryptAnon(pp, []byte("alice"), msg)
+
+	got, err := TryDecrypt(pp, aliceSK, ct)
+	if err != nil {
+		t.Fatalf("TryDecrypt failed: %v", err)
+	}
+	if !bytes.Equal(msg, got) {
+		t.Fatalf("expected decrypted message to be %x, but got %x", msg, got)
+	}
+
+	if _, err := TryDecrypt(pp, bobSK, ct); err != ErrNotRecipient {
+		t.Fatalf("expected ErrNotRecipient, but got %v", err)
+	}
+}
+
+func TestTrialDecrypt(t *testing.T) {
+	pkg, pp := NewPrivateKeyGenerator()
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+
+	sks := make([]*PrivateKey, 5)
+	for i := range sks {
+		sks[i] = pkg.Extract(fmt.Appendf(nil, "user%d", i))
+	}
+
+	for i := range sks {
+		ct := EncryptAnon(pp, fmt.Appendf(nil, "user%d", i), msg)
+		j, got, err := TrialDecrypt(pp, sks, ct)
+		if err != nil {
+			t.Fatalf("TrialDecrypt failed: %v", err)
+		}
+		if i != j {
+			t.Fatalf("expected key %d, but got %d", i, j)
+		}
+		if !bytes.Equal(msg, got) {
+			t.Fatalf("expected decrypted message to be %x, but got %x", msg, got)
+		}
+	}
+
+	ct := EncryptAnon(pp, []byte("mallory"), msg)
+	if _, _, err := TrialDecrypt(pp, sks, ct); err != ErrNotRecipient {
+		t.Fatalf("expected ErrNotRecipient, but got %v", err)
+	}
+}
+
+// Ciphertexts for different identities must have the same size and
+// structure, and share no fixed bytes that could identify the recipient.
+func TestAnonymity(t *testing.T) {
+	_, pp := NewPrivateKeyGenerator()
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+	ids := [][]byte{[]byte("a"), []byte("alice@example.com"), bytes.Repeat([]byte("x"), 1024)}
+
+	sizes := func(ct *AnonCiphertext) [3]int {
+		return [3]int{len(ct.U.BytesCompressed()), len(ct.Tag), len(ct.V)}
+	}
+
+	want := sizes(EncryptAnon(pp, ids[0], msg))
+	for _, id := range ids {
+		ct1 := EncryptAnon(pp, id, msg)
+		ct2 := EncryptAnon(pp, id, msg)
+		if got := sizes(ct1); got != want {
+			t.Fatalf("expected sizes %v, but got %v for id %q", want, got, id)
+		}
+		if ct1.U.IsEqual(ct2.U) || bytes.Equal(ct1.Tag, ct2.Tag) || bytes.Equal(ct1.V, ct2.V) {
+			t.Fatalf("two encryptions to %q share a component", id)
+		}
+		if !ct1.U.IsOnG2() {
+			t.Fatal("U is not in G2")
+		}
+
+		bct := Encrypt(pp, id, msg)
+		if len(bct.U.BytesCompressed()) != want[0] || len(bct.V) != want[2] {
+			t.Fatalf("BasicIdent ciphertext size depends on id %q", id)
+		}
+	}
+}
+
+func BenchmarkTryDecrypt(b *testing.B) {
+	pkg, pp := NewPrivateKeyGenerator()
+	bobSK := pkg.Extract([]byte("bob"))
+	ct := EncryptAnon(pp, []byte("alice"), []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345"))
+
+	for b.Loop() {
+		_, _ = TryDecrypt(pp, bobSK, ct)
+	}
+}
@@ -11,6 +11,30 @@
 // Section 4 of that paper describes the scheme.  Alin Tomescu's blog
 // [article] on pairings also provides a clear and concise description.
 //
+// [Encrypt] and [Decrypt] implement BasicIdent, which is CPA-secure: its
+// ciphertexts are malleable, and decryption of a tampered ciphertext yields
+// garbage rather than an error.  [EncryptFull] and [DecryptFull] implement
+// FullIdent, which applies the Fujisaki-Okamoto transform to BasicIdent and
+// is CCA-secure; [DecryptFull] rejects tampered ciphertexts.  Both modes use
+// the same public parameters and private keys.
+//
+// Both modes are recipient-anonymous: a ciphertext does not reveal the
+// identity it was encrypted to.  [EncryptAnon] adds a key-check tag, so that
+// a receiver can test a key against a ciphertext with [TryDecrypt], or find
+// the right key among several with [TrialDecrypt].
+//
+// The package also implements a distributed PKG that Shamir-shares the master
+// secret across n key servers (see [NewDKGParticipant]).  Any t servers
+// return [PartialKey] values for an identity, which the client verifies and
+// combines with [CombinePartialKeys] into an ordinary [PrivateKey].
+//
+// For key rotation, [EncryptEpoch] encrypts to an identity scoped to an
+// epoch, [PrivateKeyGenerator.ExtractEpoch] issues a batch of per-epoch keys,
+// and a [KeyWindow] holds a client's keys for its most recent epochs.  For
+// revocation, a [RevocationTree] implements the binary-tree scheme of
+// Boldyreva, Goyal and Kumar, in which the PKG's per-epoch update grows with
+// the number of revoked users rather than with the number of users.
+//
 // [paper]: https://eprint.iacr.org/2001/090.pdf
 // [article]: https://alinush.github.io/pairings
 package bf01
@@ -0,0 +1,147 @@
+package bf01
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"slices"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// Time-scoped identities give bf01 a coarse form of revocation: senders
+// encrypt to an identity for a given epoch, and the PKG issues each user a
+// key per epoch, so that it stops issuing keys to a user it revokes.  See
+// revocation.go for a variant in which the PKG's per-epoch work does not
+// grow with the number of users.
+
+var (
+	ErrInvalidEpochKey  = errors.New("bf01: invalid epoch key")
+	ErrEpochKeyNotFound = errors.New("bf01: no key for epoch")
+	ErrInvalidWindow    = errors.New("bf01: invalid key window size")
+)
+
+var epochIdentityPrefix = []byte("bf01-epoch")
+
+// EpochIdentity returns the identity string for id during epoch.  The epoch
+// is encoded with a fixed width before the id, so the encoding is
+// unambiguous.
+func EpochIdentity(id []byte, epoch uint64) []byte {
+	buf := make([]byte, 0, len(epochIdentityPrefix)+8+len(id))
+	buf = append(buf, epochIdentityPrefix...)
+	buf = binary.BigEndian.AppendUint64(buf, epoch)
+	buf = append(buf, id...)
+	return buf
+}
+
+type EpochCiphertext struct {
+	Epoch uint64
+	CT    *Ciphertext
+}
+
+func EncryptEpoch(pp *PublicParams, id []byte, epoch uint64, msg []byte) *EpochCiphertext {
+	return &EpochCiphertext{
+		Epoch: epoch,
+		CT:    Encrypt(pp, EpochIdentity(id, epoch), msg),
+	}
+}
+
+type EpochKey struct {
+	Id    []byte
+	Epoch uint64
+	SK    *PrivateKey
+}
+
+// ExtractEpoch issues the keys for all of ids for one epoch.
+func (pkg *PrivateKeyGenerator) ExtractEpoch(ids [][]byte, epoch uint64) []*EpochKey {
+	eks := make([]*EpochKey, len(ids))
+	for i, id := range ids {
+		eks[i] = &EpochKey{
+			Id:    bytes.Clone(id),
+			Epoch: epoch,
+			SK:    pkg.Extract(EpochIdentity(id, epoch)),
+		}
+	}
+	return eks
+}
+
+// Verify checks that the key was extracted for the key's id and epoch:
+// e(SK, g2) = e(H(id || epoch), MPK).
+func (ek *EpochKey) Verify(pp *PublicParams) error {
+	lhs := bls.Pair(ek.SK.SK, bls.G2Generator())
+	rhs := bls.Pair(blspairing.HashBytesToG1(EpochIdentity(ek.Id, ek.Epoch), nil), pp.MPK)
+	if !lhs.IsEqual(rhs) {
+		return ErrInvalidEpochKey
+	}
+	return nil
+}
+
+// KeyWindow is a client's store of its epoch keys.  It keeps the keys for
+// the Size most recent epochs, so that the client can still decrypt
+// ciphertexts from slightly earlier epochs.
+type KeyWindow struct {
+	PP   *PublicParams
+	Id   []byte
+	Size int
+
+	keys map[uint64]*PrivateKey
+}
+
+func NewKeyWindow(pp *PublicParams, id []byte, size int) (*KeyWindow, error) {
+	if size < 1 {
+		return nil, ErrInvalidWindow
+	}
+	return &KeyWindow{
+		PP:   pp,
+		Id:   bytes.Clone(id),
+		Size: size,
+		keys: make(map[uint64]*PrivateKey),
+	}, nil
+}
+
+// Add verifies the key and adds it to the window, then evicts keys that have
+// fallen out of the window.  A key that is already outside of the window is
+// ignored.
+func (w *KeyWindow) Add(ek *EpochKey) error {
+	if !bytes.Equal(ek.Id, w.Id) {
+		return ErrInvalidEpochKey
+	}
+	if err := ek.Verify(w.PP); err != nil {
+		return err
+	}
+
+	w.keys[ek.Epoch] = ek.SK
+
+	epochs := w.Epochs()
+	latest := epochs[len(epochs)-1]
+	for _, e := range epochs {
+		if latest-e >= uint64(w.Size) {
+			delete(w.keys, e)
+		}
+	}
+	return nil
+}
+
+// Epochs returns the epochs in the window, in increasing order.
+func (w *KeyWindow) Epochs() []uint64 {
+	epochs := make([]uint64, 0, len(w.keys))
+	for e := range w.keys {
+		epochs = append(epochs, e)
+	}
+	slices.Sort(epochs)
+	return epochs
+}
+
+func (w *KeyWindow) Key(epoch uint64) (*PrivateKey, bool) {
+	sk, ok := w.keys[epoch]
+	return sk, ok
+}
+
+func (w *KeyWindow) Decrypt(ct *EpochCiphertext) ([]byte, error) {
+	sk, ok := w.keys[ct.Epoch]
+	if !ok {
+		return nil, ErrEpochKeyNotFound
+	}
+	return Decrypt(w.PP, sk, ct.CT), nil
+}
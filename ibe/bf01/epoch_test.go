@@ -0,0 +1,71 @@
+package bf01
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestEpochIdentity(t *testing.T) {
+	// the epoch is fixed-width, so an id cannot spill into it
+	if bytes.Equal(EpochIdentity([]byte("a\x00"), 1), EpochIdentity([]byte("a"), 1)) {
+		t.Fatal("EpochIdentity is ambiguous")
+	}
+	if bytes.Equal(EpochIdentity([]byte("alice"), 1), EpochIdentity([]byte("alice"), 2)) {
+		t.Fatal("EpochIdentity ignores the epoch")
+	}
+}
+
+func TestKeyWindow(t *testing.T) {
+	pkg, pp := NewPrivateKeyGenerator()
+	ids := [][]byte{[]byte("alice"), []byte("bob")}
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+
+	w, err := NewKeyWindow(pp, ids[0], 3)
+	if err != nil {
+		t.Fatalf("NewKeyWindow failed: %v", err)
+	}
+	for epoch := uint64(1); epoch <= 5; epoch++ {
+		eks := pkg.ExtractEpoch(ids, epoch)
+		if err := w.Add(eks[0]); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		// bob's key is not alice's
+		if err := w.Add(eks[1]); err != ErrInvalidEpochKey {
+			t.Fatalf("expected ErrInvalidEpochKey, but got %v", err)
+		}
+	}
+
+	if got := w.Epochs(); !slices.Equal(got, []uint64{3, 4, 5}) {
+		t.Fatalf("expected epochs [3 4 5], but got %v", got)
+	}
+
+	got, err := w.Decrypt(EncryptEpoch(pp, ids[0], 4, msg))
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if !bytes.Equal(msg, got) {
+		t.Fatalf("expected decrypted message to be %x, but got %x", msg, got)
+	}
+
+	if _, err := w.Decrypt(EncryptEpoch(pp, ids[0], 2, msg)); err != ErrEpochKeyNotFound {
+		t.Fatalf("expected ErrEpochKeyNotFound for an evicted epoch, but got %v", err)
+	}
+
+	// a key for one epoch does not decrypt another
+	sk, _ := w.Key(5)
+	if got := Decrypt(pp, sk, EncryptEpoch(pp, ids[0], 6, msg).CT); bytes.Equal(msg, got) {
+		t.Fatal("a key for one epoch decrypted a ciphertext for another")
+	}
+
+	// a relabeled key fails verification
+	ek := pkg.ExtractEpoch(ids[:1], 6)[0]
+	ek.Epoch = 7
+	if err := w.Add(ek); err != ErrInvalidEpochKey {
+		t.Fatalf("expected ErrInvalidEpochKey, but got %v", err)
+	}
+
+	if _, err := NewKeyWindow(pp, ids[0], 0); err != ErrInvalidWindow {
+		t.Fatalf("expected ErrInvalidWindow, but got %v", err)
+	}
+}
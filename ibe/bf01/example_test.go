@@ -26,3 +26,43 @@ func Example() {
 	// Output:
 	// true
 }
+
+// Example_fullIdent shows the CCA-secure FullIdent mode, which detects a
+// tampered ciphertext.
+func Example_fullIdent() {
+	aliceID := []byte("alice@example.com")
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	aliceSK := pkg.Extract(aliceID)
+
+	ct := bf01.EncryptFull(pp, aliceID, msg)
+	got, err := bf01.DecryptFull(pp, aliceSK, ct)
+	fmt.Println(string(got), err)
+
+	ct.W[0] ^= 1
+	_, err = bf01.DecryptFull(pp, aliceSK, ct)
+	fmt.Println(err)
+	// Output:
+	// The quick brown fox jumps over the lazy dog. <nil>
+	// bf01: invalid ciphertext
+}
+
+// Example_anonymous shows how Alice, who holds keys for several addresses,
+// finds the one that an anonymous ciphertext was encrypted to.
+func Example_anonymous() {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	ids := [][]byte{[]byte("alice@example.com"), []byte("alice@example.org")}
+	sks := []*bf01.PrivateKey{pkg.Extract(ids[0]), pkg.Extract(ids[1])}
+
+	ct := bf01.EncryptAnon(pp, ids[1], []byte("The quick brown fox jumps over the lazy dog."))
+
+	i, got, err := bf01.TrialDecrypt(pp, sks, ct)
+	fmt.Println(i, string(got), err)
+
+	_, err = bf01.TryDecrypt(pp, sks[0], ct)
+	fmt.Println(err)
+	// Output:
+	// 1 The quick brown fox jumps over the lazy dog. <nil>
+	// bf01: key is not the ciphertext's recipient
+}
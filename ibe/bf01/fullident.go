@@ -0,0 +1,103 @@
+package bf01
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/mu"
+	"github.com/etclab/ncircl/util/blspairing"
+	"github.com/etclab/ncircl/util/bytesx"
+	"golang.org/x/crypto/hkdf"
+)
+
+// SigmaSize is the size in bytes of FullIdent's random seed, sigma.
+const SigmaSize = 32
+
+var ErrInvalidCiphertext = errors.New("bf01: invalid ciphertext")
+
+var (
+	h3DomainSepTag = []byte("bf01-H3")
+	h4DomainSepTag = []byte("bf01-H4")
+)
+
+// H_3: {0,1}^n \times {0,1}^* \rightarrow Z_q^*
+func H3(sigma, msg []byte) *bls.Scalar {
+	buf := make([]byte, 0, len(h3DomainSepTag)+len(sigma)+len(msg))
+	buf = append(buf, h3DomainSepTag...)
+	buf = append(buf, sigma...)
+	buf = append(buf, msg...)
+	return blspairing.HashBytesToScalar(buf)
+}
+
+// H_4: {0,1}^n \rightarrow {0,1}^*
+func H4(sigma []byte, numBytes int) []byte {
+	kdf := hkdf.New(sha256.New, sigma, nil, h4DomainSepTag)
+
+	h := make([]byte, numBytes)
+	_, err := io.ReadFull(kdf, h)
+	if err != nil {
+		mu.Panicf("io.ReadFull failed: %v", err)
+	}
+
+	return h
+}
+
+// FullCiphertext is a FullIdent ciphertext: U = rP, V = sigma XOR
+// H_T(g_id^r), and W = M XOR H_4(sigma), where r = H_3(sigma, M).
+type FullCiphertext struct {
+	U *bls.G2
+	V []byte
+	W []byte
+}
+
+// EncryptFull encrypts with FullIdent, the Fujisaki-Okamoto transform of
+// BasicIdent (Section 4.2 of the paper), which is CCA-secure.
+func EncryptFull(pp *PublicParams, id []byte, msg []byte) *FullCiphertext {
+	sigma := bytesx.Random(SigmaSize)
+	r := H3(sigma, msg)
+
+	u := new(bls.G2)
+	u.ScalarMult(r, bls.G2Generator())
+
+	pkId := bls.Pair(blspairing.HashBytesToG1(id, nil), pp.MPK)
+	tmp := new(bls.Gt)
+	tmp.Exp(pkId, r)
+	v := HT(tmp, SigmaSize)
+	bytesx.Xor(v, sigma)
+
+	w := H4(sigma, len(msg))
+	bytesx.Xor(w, msg)
+
+	return &FullCiphertext{
+		U: u,
+		V: v,
+		W: w,
+	}
+}
+
+// DecryptFull recovers sigma and the message, then re-derives r and checks
+// that U = rP.  It returns [ErrInvalidCiphertext] if the check fails, which
+// is the case for a ciphertext that was tampered with or that was encrypted
+// to another identity.
+func DecryptFull(pp *PublicParams, sk *PrivateKey, ct *FullCiphertext) ([]byte, error) {
+	if len(ct.V) != SigmaSize {
+		return nil, ErrInvalidCiphertext
+	}
+
+	sigma := HT(bls.Pair(sk.SK, ct.U), SigmaSize)
+	bytesx.Xor(sigma, ct.V)
+
+	msg := H4(sigma, len(ct.W))
+	bytesx.Xor(msg, ct.W)
+
+	r := H3(sigma, msg)
+	u := new(bls.G2)
+	u.ScalarMult(r, bls.G2Generator())
+	if !u.IsEqual(ct.U) {
+		return nil, ErrInvalidCiphertext
+	}
+
+	return msg, nil
+}
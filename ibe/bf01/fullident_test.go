@@ -0,0 +1,79 @@
+package bf01
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecryptFull(t *testing.T) {
+	pkg, pp := NewPrivateKeyGenerator()
+	bobSK := pkg.Extract([]byte("bob"))
+
+	for _, msg := range [][]byte{
+		{},
+		[]byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345"),
+		[]byte("The quick brown fox jumps over the lazy dog."),
+	} {
+		ct := EncryptFull(pp, []byte("bob"), msg)
+		got, err := DecryptFull(pp, bobSK, ct)
+		if err != nil {
+			t.Fatalf("DecryptFull failed: %v", err)
+		}
+		if !bytes.Equal(msg, got) {
+			t.Fatalf("expected decrypted message to be %x, but got %x", msg, got)
+		}
+	}
+}
+
+func TestDecryptFull_tampered(t *testing.T) {
+	pkg, pp := NewPrivateKeyGenerator()
+	bobSK := pkg.Extract([]byte("bob"))
+	aliceSK := pkg.Extract([]byte("alice"))
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+
+	trials := []struct {
+		name   string
+		sk     *PrivateKey
+		tamper func(ct *FullCiphertext)
+	}{
+		{"U", bobSK, func(ct *FullCiphertext) { ct.U.Double() }},
+		{"V", bobSK, func(ct *FullCiphertext) { ct.V[0] ^= 1 }},
+		{"W", bobSK, func(ct *FullCiphertext) { ct.W[0] ^= 1 }},
+		{"short V", bobSK, func(ct *FullCiphertext) { ct.V = ct.V[1:] }},
+		{"truncated W", bobSK, func(ct *FullCiphertext) { ct.W = ct.W[:len(ct.W)-1] }},
+		{"wrong key", aliceSK, func(ct *FullCiphertext) {}},
+	}
+
+	for _, trial := range trials {
+		t.Run(trial.name, func(t *testing.T) {
+			ct := EncryptFull(pp, []byte("bob"), msg)
+			trial.tamper(ct)
+			if _, err := DecryptFull(pp, trial.sk, ct); err != ErrInvalidCiphertext {
+				t.Fatalf("expected ErrInvalidCiphertext, but got %v", err)
+			}
+		})
+	}
+}
+
+func BenchmarkEncryptFull(b *testing.B) {
+	_, pp := NewPrivateKeyGenerator()
+	id := []byte("test@example.com")
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+	for b.Loop() {
+		_ = EncryptFull(pp, id, msg)
+	}
+}
+
+func BenchmarkDecryptFull(b *testing.B) {
+	pkg, pp := NewPrivateKeyGenerator()
+	id := []byte("test@example.com")
+	idSK := pkg.Extract(id)
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+	ct := EncryptFull(pp, id, msg)
+	for b.Loop() {
+		_, err := DecryptFull(pp, idSK, ct)
+		if err != nil {
+			b.Fatalf("DecryptFull failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package bf01
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/util/blspairing"
+	"github.com/etclab/ncircl/util/bytesx"
+)
+
+// Revocable IBE, after the binary-tree construction of Boldyreva, Goyal and
+// Kumar (CCS 2008):
+//
+//	@inproceedings{08-ccs-ibe_efficient_revocation,
+//	    title = {Identity-Based Encryption with Efficient Revocation},
+//	    author = {Boldyreva, Alexandra and Goyal, Vipul and Kumar, Virendra},
+//	    booktitle = {ACM Conference on Computer and Communications Security (CCS)},
+//	    year = {2008},
+//	}
+//
+// Users are the leaves of a binary tree of depth d.  For each tree node, the
+// PKG splits the master secret s into an identity share a and an epoch share
+// s - a.  A user's long-term key holds the identity share for each node on
+// the user's path; each epoch, the PKG publishes an update key that holds the
+// epoch share for a minimal set of nodes that covers exactly the unrevoked
+// users (KUNodes in the paper).  A user combines the two shares of a common
+// node into a decryption key for the epoch.  The update has
+// O(r log(N/r)) components for r revoked users out of N = 2^d.
+//
+// The paper realizes the split with Fuzzy IBE; this package uses the
+// random-oracle form of that scheme: every share is blinded with fresh
+// randomness, so that revoked users cannot pool their shares.  The scheme
+// reuses the [PublicParams] and master secret of bf01, but its ciphertexts
+// are not bf01 ciphertexts: see [EncryptRevocable].
+
+// MaxRevocationDepth is the maximum depth of a [RevocationTree], which has
+// 2^depth leaves.
+const MaxRevocationDepth = 30
+
+var (
+	ErrInvalidDepth  = errors.New("bf01: invalid revocation tree depth")
+	ErrTreeFull      = errors.New("bf01: revocation tree is full")
+	ErrUserExists    = errors.New("bf01: user already in revocation tree")
+	ErrUnknownUser   = errors.New("bf01: user not in revocation tree")
+	ErrRevoked       = errors.New("bf01: user is revoked for epoch")
+	ErrEpochMismatch = errors.New("bf01: ciphertext and key are for different epochs")
+)
+
+var (
+	revIdDomainSepTag    = []byte("bf01-revocable-id")
+	revEpochDomainSepTag = []byte("bf01-revocable-epoch")
+)
+
+func hashRevId(id []byte) *bls.G2 {
+	return blspairing.HashBytesToG2(id, revIdDomainSepTag)
+}
+
+func hashRevEpoch(epoch uint64) *bls.G2 {
+	return blspairing.HashBytesToG2(binary.BigEndian.AppendUint64(nil, epoch), revEpochDomainSepTag)
+}
+
+// Nodes are numbered as in a binary heap: the root is 1, and the children of
+// node x are 2x and 2x+1.  The leaves of a tree of depth d are 2^d through
+// 2^(d+1) - 1.
+
+// path returns the nodes from the root to leaf.
+func path(leaf int) []int {
+	var p []int
+	for x := leaf; x >= 1; x /= 2 {
+		p = append(p, x)
+	}
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+	return p
+}
+
+type revokedUser struct {
+	leaf  int
+	epoch uint64
+}
+
+// RevocationTree is the PKG's state for revocable IBE.
+type RevocationTree struct {
+	PKG   *PrivateKeyGenerator
+	Depth int
+
+	leaves  map[string]int
+	revoked map[string]revokedUser
+	next    int
+	shares  map[int]*bls.Scalar
+}
+
+// NewRevocationTree creates a tree with room for 2^depth users.  The depth
+// must be in [0, MaxRevocationDepth].
+func NewRevocationTree(pkg *PrivateKeyGenerator, depth int) (*RevocationTree, error) {
+	if depth < 0 || depth > MaxRevocationDepth {
+		return nil, ErrInvalidDepth
+	}
+	return &RevocationTree{
+		PKG:     pkg,
+		Depth:   depth,
+		leaves:  make(map[string]int),
+		revoked: make(map[string]revokedUser),
+		next:    1 << depth,
+		shares:  make(map[int]*bls.Scalar),
+	}, nil
+}
+
+// share returns the identity share a for node x, choosing it on first use.
+func (rt *RevocationTree) share(x int) *bls.Scalar {
+	a, ok := rt.shares[x]
+	if !ok {
+		a = blspairing.NewRandomScalar()
+		rt.shares[x] = a
+	}
+	return a
+}
+
+// NodeKey is one node's component of a [RevocableKey] or [UpdateKey]: a share
+// of the master secret, blinded as (share*g2 + rho*H(x), rho*g1) where x is
+// the identity or the epoch.
+type NodeKey struct {
+	Node int
+	D    *bls.G2
+	R    *bls.G1
+}
+
+func newNodeKey(node int, share *bls.Scalar, h *bls.G2) *NodeKey {
+	rho := blspairing.NewRandomScalar()
+
+	d := new(bls.G2)
+	d.ScalarMult(rho, h)
+	tmp := new(bls.G2)
+	tmp.ScalarMult(share, bls.G2Generator())
+	d.Add(d, tmp)
+
+	r := new(bls.G1)
+	r.ScalarMult(rho, bls.G1Generator())
+
+	return &NodeKey{Node: node, D: d, R: r}
+}
+
+// RevocableKey is a user's long-term key: the identity shares for the nodes
+// on the path from the root to the user's leaf.
+type RevocableKey struct {
+	Id    []byte
+	Nodes []*NodeKey
+}
+
+// AddUser assigns id the next free leaf and returns its long-term key.
+func (rt *RevocationTree) AddUser(id []byte) (*RevocableKey, error) {
+	if _, ok := rt.leaves[string(id)]; ok {
+		return nil, ErrUserExists
+	}
+	if rt.next >= 1<<(rt.Depth+1) {
+		return nil, ErrTreeFull
+	}
+	leaf := rt.next
+	rt.next++
+	rt.leaves[string(id)] = leaf
+
+	h := hashRevId(id)
+	key := &RevocableKey{Id: bytes.Clone(id)}
+	for _, x := range path(leaf) {
+		key.Nodes = append(key.Nodes, newNodeKey(x, rt.share(x), h))
+	}
+	return key, nil
+}
+
+// Revoke revokes id from epoch onwards.
+func (rt *RevocationTree) Revoke(id []byte, epoch uint64) error {
+	leaf, ok := rt.leaves[string(id)]
+	if !ok {
+		return ErrUnknownUser
+	}
+	rt.revoked[string(id)] = revokedUser{leaf: leaf, epoch: epoch}
+	return nil
+}
+
+// coverNodes returns the nodes whose subtrees together contain exactly the
+// leaves of users not revoked at epoch (the KUNodes algorithm).
+func (rt *RevocationTree) coverNodes(epoch uint64) []int {
+	x := make(map[int]bool)
+	for _, ru := range rt.revoked {
+		if ru.epoch <= epoch {
+			for _, n := range path(ru.leaf) {
+				x[n] = true
+			}
+		}
+	}
+	if len(x) == 0 {
+		return []int{1}
+	}
+
+	firstLeaf := 1 << rt.Depth
+	var y []int
+	for n := range x {
+		if n >= firstLeaf {
+			continue
+		}
+		for _, c := range []int{2 * n, 2*n + 1} {
+			if !x[c] {
+				y = append(y, c)
+			}
+		}
+	}
+	return y
+}
+
+// UpdateKey holds the epoch shares that the PKG publishes for an epoch.
+type UpdateKey struct {
+	Epoch uint64
+	Nodes []*NodeKey
+}
+
+// UpdateKey returns the update key for epoch, which does not let users who
+// are revoked at epoch decrypt.
+func (rt *RevocationTree) UpdateKey(epoch uint64) *UpdateKey {
+	h := hashRevEpoch(epoch)
+	uk := &UpdateKey{Epoch: epoch}
+	for _, x := range rt.coverNodes(epoch) {
+		s := new(bls.Scalar)
+		s.Sub(rt.PKG.MSK, rt.share(x))
+		uk.Nodes = append(uk.Nodes, newNodeKey(x, s, h))
+	}
+	return uk
+}
+
+// RevocableDecryptionKey combines the identity and epoch shares of one node.
+type RevocableDecryptionKey struct {
+	Epoch uint64
+	Id    *NodeKey
+	Time  *NodeKey
+}
+
+// DecryptionKey finds a node in both the user's key and the update key, and
+// returns [ErrRevoked] if there is none.
+func DecryptionKey(key *RevocableKey, uk *UpdateKey) (*RevocableDecryptionKey, error) {
+	for _, nk := range key.Nodes {
+		for _, uk2 := range uk.Nodes {
+			if nk.Node == uk2.Node {
+				return &RevocableDecryptionKey{
+					Epoch: uk.Epoch,
+					Id:    nk,
+					Time:  uk2,
+				}, nil
+			}
+		}
+	}
+	return nil, ErrRevoked
+}
+
+// RevocableCiphertext is (U, C1, C2, V) = (r*g1, r*H(id), r*H(epoch),
+// M XOR H_T(e(g1, MPK)^r)).
+type RevocableCiphertext struct {
+	Epoch uint64
+	U     *bls.G1
+	C1    *bls.G2
+	C2    *bls.G2
+	V     []byte
+}
+
+func EncryptRevocable(pp *PublicParams, id []byte, epoch uint64, msg []byte) *RevocableCiphertext {
+	r := blspairing.NewRandomScalar()
+
+	u := new(bls.G1)
+	u.ScalarMult(r, bls.G1Generator())
+
+	c1 := hashRevId(id)
+	c1.ScalarMult(r, c1)
+
+	c2 := hashRevEpoch(epoch)
+	c2.ScalarMult(r, c2)
+
+	z := bls.Pair(u, pp.MPK)
+	v := HT(z, len(msg))
+	bytesx.Xor(v, msg)
+
+	return &RevocableCiphertext{
+		Epoch: epoch,
+		U:     u,
+		C1:    c1,
+		C2:    c2,
+		V:     v,
+	}
+}
+
+// DecryptRevocable recovers e(g1, MPK)^r as
+// e(U, D_id) / e(R_id, C1) * e(U, D_t) / e(R_t, C2).
+func DecryptRevocable(_ *PublicParams, dk *RevocableDecryptionKey, ct *RevocableCiphertext) ([]byte, error) {
+	if dk.Epoch != ct.Epoch {
+		return nil, ErrEpochMismatch
+	}
+
+	tmp := new(bls.G2)
+	tmp.Add(dk.Id.D, dk.Time.D)
+	z := bls.Pair(ct.U, tmp)
+
+	den := bls.Pair(dk.Id.R, ct.C1)
+	den.Mul(den, bls.Pair(dk.Time.R, ct.C2))
+	den.Inv(den)
+	z.Mul(z, den)
+
+	msg := HT(z, len(ct.V))
+	bytesx.Xor(msg, ct.V)
+	return msg, nil
+}
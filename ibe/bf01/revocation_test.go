@@ -0,0 +1,180 @@
+package bf01
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestRevocationTree(t *testing.T) {
+	pkg, pp := NewPrivateKeyGenerator()
+	rt, err := NewRevocationTree(pkg, 3)
+	if err != nil {
+		t.Fatalf("NewRevocationTree failed: %v", err)
+	}
+
+	ids := make([][]byte, 8)
+	keys := make([]*RevocableKey, 8)
+	for i := range ids {
+		ids[i] = fmt.Appendf(nil, "user%d", i)
+		keys[i], err = rt.AddUser(ids[i])
+		if err != nil {
+			t.Fatalf("AddUser failed: %v", err)
+		}
+	}
+	if _, err := rt.AddUser([]byte("user9")); err != ErrTreeFull {
+		t.Fatalf("expected ErrTreeFull, but got %v", err)
+	}
+	if _, err := rt.AddUser(ids[0]); err != ErrUserExists {
+		t.Fatalf("expected ErrUserExists, but got %v", err)
+	}
+
+	if n := len(rt.UpdateKey(1).Nodes); n != 1 {
+		t.Fatalf("expected 1 update node without revocations, but got %d", n)
+	}
+
+	if err := rt.Revoke(ids[2], 2); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if err := rt.Revoke(ids[5], 3); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if err := rt.Revoke([]byte("nobody"), 3); err != ErrUnknownUser {
+		t.Fatalf("expected ErrUnknownUser, but got %v", err)
+	}
+
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+	for epoch := uint64(1); epoch <= 3; epoch++ {
+		uk := rt.UpdateKey(epoch)
+		for i, id := range ids {
+			revoked := (i == 2 && epoch >= 2) || (i == 5 && epoch >= 3)
+			dk, err := DecryptionKey(keys[i], uk)
+			if revoked {
+				if err != ErrRevoked {
+					t.Fatalf("epoch %d, %s: expected ErrRevoked, but got %v", epoch, id, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("epoch %d, %s: DecryptionKey failed: %v", epoch, id, err)
+			}
+
+			got, err := DecryptRevocable(pp, dk, EncryptRevocable(pp, id, epoch, msg))
+			if err != nil {
+				t.Fatalf("DecryptRevocable failed: %v", err)
+			}
+			if !bytes.Equal(msg, got) {
+				t.Fatalf("epoch %d, %s: expected decrypted message to be %x, but got %x", epoch, id, msg, got)
+			}
+
+			if _, err := DecryptRevocable(pp, dk, EncryptRevocable(pp, id, epoch+1, msg)); err != ErrEpochMismatch {
+				t.Fatalf("expected ErrEpochMismatch, but got %v", err)
+			}
+		}
+	}
+}
+
+// coverNodes must cover every unrevoked leaf exactly once, and no revoked
+// leaf.
+func TestCoverNodes(t *testing.T) {
+	pkg, _ := NewPrivateKeyGenerator()
+	rt, err := NewRevocationTree(pkg, 4)
+	if err != nil {
+		t.Fatalf("NewRevocationTree failed: %v", err)
+	}
+	for i := range 16 {
+		if _, err := rt.AddUser(fmt.Appendf(nil, "user%d", i)); err != nil {
+			t.Fatalf("AddUser failed: %v", err)
+		}
+	}
+	revoked := map[int]bool{0: true, 1: true, 7: true, 12: true}
+	for i := range revoked {
+		if err := rt.Revoke(fmt.Appendf(nil, "user%d", i), 0); err != nil {
+			t.Fatalf("Revoke failed: %v", err)
+		}
+	}
+
+	cover := make(map[int]bool)
+	for _, x := range rt.coverNodes(0) {
+		cover[x] = true
+	}
+	for i := range 16 {
+		n := 0
+		for _, x := range path(16 + i) {
+			if cover[x] {
+				n++
+			}
+		}
+		if revoked[i] && n != 0 {
+			t.Fatalf("revoked leaf %d is covered", i)
+		}
+		if !revoked[i] && n != 1 {
+			t.Fatalf("unrevoked leaf %d is covered %d times", i, n)
+		}
+	}
+}
+
+// A revoked user cannot pair its identity share for one node with the epoch
+// share of another.
+func TestDecryptRevocable_mixedNodes(t *testing.T) {
+	pkg, pp := NewPrivateKeyGenerator()
+	rt, err := NewRevocationTree(pkg, 1)
+	if err != nil {
+		t.Fatalf("NewRevocationTree failed: %v", err)
+	}
+	alice, err := rt.AddUser([]byte("alice"))
+	if err != nil {
+		t.Fatalf("AddUser failed: %v", err)
+	}
+	if _, err := rt.AddUser([]byte("bob")); err != nil {
+		t.Fatalf("AddUser failed: %v", err)
+	}
+	if err := rt.Revoke([]byte("alice"), 1); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+
+	uk := rt.UpdateKey(1)
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+	ct := EncryptRevocable(pp, []byte("alice"), 1, msg)
+	for _, nk := range alice.Nodes {
+		dk := &RevocableDecryptionKey{Epoch: 1, Id: nk, Time: uk.Nodes[0]}
+		got, err := DecryptRevocable(pp, dk, ct)
+		if err != nil {
+			t.Fatalf("DecryptRevocable failed: %v", err)
+		}
+		if bytes.Equal(msg, got) {
+			t.Fatal("a revoked user decrypted by mixing nodes")
+		}
+	}
+}
+
+func TestRevocationTreeInvalidDepth(t *testing.T) {
+	pkg, _ := NewPrivateKeyGenerator()
+	for _, depth := range []int{-1, MaxRevocationDepth + 1} {
+		if _, err := NewRevocationTree(pkg, depth); err != ErrInvalidDepth {
+			t.Fatalf("depth %d: expected ErrInvalidDepth, but got %v", depth, err)
+		}
+	}
+}
+
+func BenchmarkUpdateKey(b *testing.B) {
+	pkg, _ := NewPrivateKeyGenerator()
+	rt, err := NewRevocationTree(pkg, 10)
+	if err != nil {
+		b.Fatalf("NewRevocationTree failed: %v", err)
+	}
+	for i := range 32 {
+		id := fmt.Appendf(nil, "user%d", i)
+		if _, err := rt.AddUser(id); err != nil {
+			b.Fatalf("AddUser failed: %v", err)
+		}
+		if i%8 == 0 {
+			if err := rt.Revoke(id, 0); err != nil {
+				b.Fatalf("Revoke failed: %v", err)
+			}
+		}
+	}
+	for b.Loop() {
+		_ = rt.UpdateKey(1)
+	}
+}
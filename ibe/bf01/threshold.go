@@ -0,0 +1,293 @@
+package bf01
+
+import (
+	"errors"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// A distributed PKG (Section 6 of the paper) Shamir-shares the master secret
+// across n key servers, so that no single server can extract private keys.
+// The servers create the sharing with a joint-Feldman distributed key
+// generation (DKG): each server deals a random degree t-1 polynomial, and
+// each server's key share is the sum of the shares dealt to it.  The master
+// secret itself is never reconstructed.
+//
+// To extract a private key, a client asks each of t servers for a
+// [PartialKey], checks each one against the server's verification key, and
+// combines them with [CombinePartialKeys].  The combined key is an ordinary
+// [PrivateKey], and the DKG's [PublicParams] are ordinary public parameters,
+// so [Encrypt] and [Decrypt] are unchanged.
+//
+// The DKG below does not handle complaints: a participant that receives an
+// invalid deal learns so from [DKGParticipant.Receive], but resolving the
+// dispute (and disqualifying the dealer) is left to the caller.
+
+var (
+	ErrInvalidThreshold     = errors.New("bf01: invalid threshold")
+	ErrInvalidIndex         = errors.New("bf01: invalid key server index")
+	ErrInvalidShare         = errors.New("bf01: invalid DKG share")
+	ErrDuplicateDeal        = errors.New("bf01: duplicate DKG deal")
+	ErrMissingDeals         = errors.New("bf01: missing DKG deals")
+	ErrInvalidPartialKey    = errors.New("bf01: invalid partial key")
+	ErrDuplicatePartialKey  = errors.New("bf01: duplicate partial key")
+	ErrNotEnoughPartialKeys = errors.New("bf01: not enough partial keys")
+)
+
+// ThresholdParams are the public outputs of the DKG.  VKs[i-1] is the
+// verification key x_i * g2 of the server with index i, where x_i is that
+// server's key share.
+type ThresholdParams struct {
+	T   int
+	N   int
+	PP  *PublicParams
+	VKs []*bls.G2
+}
+
+// KeyShare is a key server's share of the master secret.  Indices run from
+// 1 to n.
+type KeyShare struct {
+	Index int
+	X     *bls.Scalar
+}
+
+// DKGDeal is what a dealer sends to a recipient: the Feldman commitments to
+// the dealer's polynomial (the same for all recipients), and the
+// recipient's share, which must be sent privately.
+type DKGDeal struct {
+	Dealer      int
+	Commitments []*bls.G2
+	Share       *bls.Scalar
+}
+
+// DKGParticipant holds one key server's state during the DKG.
+type DKGParticipant struct {
+	Index int
+	T     int
+	N     int
+
+	poly        []*bls.Scalar
+	shares      map[int]*bls.Scalar
+	commitments map[int][]*bls.G2
+}
+
+func checkThreshold(t, n int) error {
+	if t < 1 || t > n {
+		return ErrInvalidThreshold
+	}
+	return nil
+}
+
+// evalPoly evaluates the polynomial with coefficients coeffs at x.
+func evalPoly(coeffs []*bls.Scalar, x *bls.Scalar) *bls.Scalar {
+	y := new(bls.Scalar)
+	for k := len(coeffs) - 1; k >= 0; k-- {
+		y.Mul(y, x)
+		y.Add(y, coeffs[k])
+	}
+	return y
+}
+
+// evalCommitments evaluates the polynomial "in the exponent": it returns
+// f(x) * g2, given the commitments coeffs[k] * g2.
+func evalCommitments(commitments []*bls.G2, x *bls.Scalar) *bls.G2 {
+	y := blspairing.NewG2Identity()
+	for k := len(commitments) - 1; k >= 0; k-- {
+		y.ScalarMult(x, y)
+		y.Add(y, commitments[k])
+	}
+	return y
+}
+
+// NewDKGParticipant starts the DKG for the server with the given index, which
+// is in [1, n].  The participant deals to itself immediately.
+func NewDKGParticipant(t, n, index int) (*DKGParticipant, error) {
+	if err := checkThreshold(t, n); err != nil {
+		return nil, err
+	}
+	if index < 1 || index > n {
+		return nil, ErrInvalidIndex
+	}
+
+	p := &DKGParticipant{
+		Index:       index,
+		T:           t,
+		N:           n,
+		poly:        make([]*bls.Scalar, t),
+		shares:      make(map[int]*bls.Scalar),
+		commitments: make(map[int][]*bls.G2),
+	}
+	for k := range p.poly {
+		p.poly[k] = blspairing.NewRandomScalar()
+	}
+
+	self, err := p.Deal(index)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Receive(self); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Deal returns this participant's deal for the recipient with the given
+// index.
+func (p *DKGParticipant) Deal(recipient int) (*DKGDeal, error) {
+	if recipient < 1 || recipient > p.N {
+		return nil, ErrInvalidIndex
+	}
+
+	commitments := make([]*bls.G2, len(p.poly))
+	for k, a := range p.poly {
+		commitments[k] = new(bls.G2)
+		commitments[k].ScalarMult(a, bls.G2Generator())
+	}
+
+	return &DKGDeal{
+		Dealer:      p.Index,
+		Commitments: commitments,
+		Share:       evalPoly(p.poly, blspairing.NewScalarFromInt(recipient)),
+	}, nil
+}
+
+// Receive verifies a deal addressed to this participant against the
+// dealer's commitments, and returns [ErrInvalidShare] if the share is
+// inconsistent with them.
+func (p *DKGParticipant) Receive(deal *DKGDeal) error {
+	if deal.Dealer < 1 || deal.Dealer > p.N {
+		return ErrInvalidIndex
+	}
+	if _, ok := p.shares[deal.Dealer]; ok {
+		return ErrDuplicateDeal
+	}
+	if len(deal.Commitments) != p.T {
+		return ErrInvalidShare
+	}
+
+	lhs := new(bls.G2)
+	lhs.ScalarMult(deal.Share, bls.G2Generator())
+	rhs := evalCommitments(deal.Commitments, blspairing.NewScalarFromInt(p.Index))
+	if !lhs.IsEqual(rhs) {
+		return ErrInvalidShare
+	}
+
+	p.shares[deal.Dealer] = blspairing.CloneScalar(deal.Share)
+	p.commitments[deal.Dealer] = deal.Commitments
+	return nil
+}
+
+// Finish completes the DKG once the participant has received a valid deal
+// from every server.  All honest participants compute the same
+// [ThresholdParams].
+func (p *DKGParticipant) Finish() (*KeyShare, *ThresholdParams, error) {
+	if len(p.shares) != p.N {
+		return nil, nil, ErrMissingDeals
+	}
+
+	x := new(bls.Scalar)
+	for _, s := range p.shares {
+		x.Add(x, s)
+	}
+
+	// the commitments to the sum of the dealers' polynomials
+	sum := make([]*bls.G2, p.T)
+	for k := range sum {
+		sum[k] = blspairing.NewG2Identity()
+		for _, c := range p.commitments {
+			sum[k].Add(sum[k], c[k])
+		}
+	}
+
+	tp := &ThresholdParams{
+		T:   p.T,
+		N:   p.N,
+		PP:  &PublicParams{MPK: sum[0]},
+		VKs: make([]*bls.G2, p.N),
+	}
+	for i := range tp.VKs {
+		tp.VKs[i] = evalCommitments(sum, blspairing.NewScalarFromInt(i+1))
+	}
+
+	return &KeyShare{Index: p.Index, X: x}, tp, nil
+}
+
+// PartialKey is a key server's share x_i * H(id) of the private key for id.
+type PartialKey struct {
+	Index int
+	SK    *bls.G1
+}
+
+func (ks *KeyShare) PartialExtract(id []byte) *PartialKey {
+	sk := new(bls.G1)
+	sk.ScalarMult(ks.X, blspairing.HashBytesToG1(id, nil))
+	return &PartialKey{Index: ks.Index, SK: sk}
+}
+
+// Verify checks the partial key against its server's verification key:
+// e(SK_i, g2) = e(H(id), VK_i).  The pairing check serves as the proof that
+// the server used its key share.
+func (pk *PartialKey) Verify(tp *ThresholdParams, id []byte) error {
+	if pk.Index < 1 || pk.Index > tp.N {
+		return ErrInvalidIndex
+	}
+
+	lhs := bls.Pair(pk.SK, bls.G2Generator())
+	rhs := bls.Pair(blspairing.HashBytesToG1(id, nil), tp.VKs[pk.Index-1])
+	if !lhs.IsEqual(rhs) {
+		return ErrInvalidPartialKey
+	}
+	return nil
+}
+
+// lagrangeAtZero returns the Lagrange coefficient for xs[i] when
+// interpolating at 0.
+func lagrangeAtZero(xs []*bls.Scalar, i int) *bls.Scalar {
+	num := blspairing.NewScalarOne()
+	den := blspairing.NewScalarOne()
+	var tmp bls.Scalar
+	for j, x := range xs {
+		if j == i {
+			continue
+		}
+		num.Mul(num, x)
+		tmp.Sub(x, xs[i])
+		den.Mul(den, &tmp)
+	}
+	den.Inv(den)
+	num.Mul(num, den)
+	return num
+}
+
+// CombinePartialKeys verifies the partial keys and interpolates the first t
+// of them into the private key for id.
+func CombinePartialKeys(tp *ThresholdParams, id []byte, partials []*PartialKey) (*PrivateKey, error) {
+	if len(partials) < tp.T {
+		return nil, ErrNotEnoughPartialKeys
+	}
+	partials = partials[:tp.T]
+
+	seen := make(map[int]bool)
+	xs := make([]*bls.Scalar, len(partials))
+	for i, pk := range partials {
+		if seen[pk.Index] {
+			return nil, ErrDuplicatePartialKey
+		}
+		seen[pk.Index] = true
+		if err := pk.Verify(tp, id); err != nil {
+			return nil, err
+		}
+		xs[i] = blspairing.NewScalarFromInt(pk.Index)
+	}
+
+	sk := blspairing.NewG1Identity()
+	tmp := new(bls.G1)
+	for i, pk := range partials {
+		tmp.ScalarMult(lagrangeAtZero(xs, i), pk.SK)
+		sk.Add(sk, tmp)
+	}
+
+	return &PrivateKey{SK: sk}, nil
+}
@@ -0,0 +1,181 @@
+package bf01
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// runDKG runs the DKG among n honest participants.
+func runDKG(t testing.TB, threshold, n int) ([]*KeyShare, *ThresholdParams) {
+	ps := make([]*DKGParticipant, n)
+	for i := range ps {
+		p, err := NewDKGParticipant(threshold, n, i+1)
+		if err != nil {
+			t.Fatalf("NewDKGParticipant failed: %v", err)
+		}
+		ps[i] = p
+	}
+
+	for _, dealer := range ps {
+		for _, recipient := range ps {
+			if dealer == recipient {
+				continue
+			}
+			deal, err := dealer.Deal(recipient.Index)
+			if err != nil {
+				t.Fatalf("Deal failed: %v", err)
+			}
+			if err := recipient.Receive(deal); err != nil {
+				t.Fatalf("Receive failed: %v", err)
+			}
+		}
+	}
+
+	shares := make([]*KeyShare, n)
+	var tp *ThresholdParams
+	for i, p := range ps {
+		ks, tpi, err := p.Finish()
+		if err != nil {
+			t.Fatalf("Finish failed: %v", err)
+		}
+		if tp != nil && !tp.PP.MPK.IsEqual(tpi.PP.MPK) {
+			t.Fatal("participants disagree on the master public key")
+		}
+		shares[i], tp = ks, tpi
+	}
+	return shares, tp
+}
+
+func TestThresholdExtract(t *testing.T) {
+	trials := []struct {
+		threshold int
+		n         int
+	}{
+		{1, 1},
+		{1, 3},
+		{2, 3},
+		{3, 5},
+		{5, 5},
+	}
+
+	id := []byte("bob")
+	msg := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ012345")
+
+	for _, trial := range trials {
+		t.Run(fmt.Sprintf("%d-of-%d", trial.threshold, trial.n), func(t *testing.T) {
+			shares, tp := runDKG(t, trial.threshold, trial.n)
+
+			// use the last t servers, to show that any subset works
+			var partials []*PartialKey
+			for _, ks := range shares[trial.n-trial.threshold:] {
+				partials = append(partials, ks.PartialExtract(id))
+			}
+			sk, err := CombinePartialKeys(tp, id, partials)
+			if err != nil {
+				t.Fatalf("CombinePartialKeys failed: %v", err)
+			}
+
+			ct := Encrypt(tp.PP, id, msg)
+			if got := Decrypt(tp.PP, sk, ct); !bytes.Equal(msg, got) {
+				t.Fatalf("expected decrypted message to be %x, but got %x", msg, got)
+			}
+		})
+	}
+}
+
+func TestCombinePartialKeys_errors(t *testing.T) {
+	shares, tp := runDKG(t, 3, 5)
+	id := []byte("bob")
+
+	var partials []*PartialKey
+	for _, ks := range shares {
+		partials = append(partials, ks.PartialExtract(id))
+	}
+
+	if _, err := CombinePartialKeys(tp, id, partials[:2]); err != ErrNotEnoughPartialKeys {
+		t.Fatalf("expected ErrNotEnoughPartialKeys, but got %v", err)
+	}
+
+	dup := []*PartialKey{partials[0], partials[1], partials[0]}
+	if _, err := CombinePartialKeys(tp, id, dup); err != ErrDuplicatePartialKey {
+		t.Fatalf("expected ErrDuplicatePartialKey, but got %v", err)
+	}
+
+	// a partial key for another identity
+	other := shares[2].PartialExtract([]byte("alice"))
+	if _, err := CombinePartialKeys(tp, id, []*PartialKey{partials[0], partials[1], other}); err != ErrInvalidPartialKey {
+		t.Fatalf("expected ErrInvalidPartialKey, but got %v", err)
+	}
+
+	// a server that claims another server's index
+	forged := &PartialKey{Index: 4, SK: partials[2].SK}
+	if err := forged.Verify(tp, id); err != ErrInvalidPartialKey {
+		t.Fatalf("expected ErrInvalidPartialKey, but got %v", err)
+	}
+}
+
+func TestDKGParticipant_Receive(t *testing.T) {
+	if _, err := NewDKGParticipant(4, 3, 1); err != ErrInvalidThreshold {
+		t.Fatalf("expected ErrInvalidThreshold, but got %v", err)
+	}
+	if _, err := NewDKGParticipant(2, 3, 4); err != ErrInvalidIndex {
+		t.Fatalf("expected ErrInvalidIndex, but got %v", err)
+	}
+
+	alice, err := NewDKGParticipant(2, 3, 1)
+	if err != nil {
+		t.Fatalf("NewDKGParticipant failed: %v", err)
+	}
+	bob, err := NewDKGParticipant(2, 3, 2)
+	if err != nil {
+		t.Fatalf("NewDKGParticipant failed: %v", err)
+	}
+
+	deal, err := alice.Deal(bob.Index)
+	if err != nil {
+		t.Fatalf("Deal failed: %v", err)
+	}
+
+	bad := *deal
+	bad.Share = blspairing.NewRandomScalar()
+	if err := bob.Receive(&bad); err != ErrInvalidShare {
+		t.Fatalf("expected ErrInvalidShare, but got %v", err)
+	}
+
+	// a deal for another recipient
+	wrong, err := alice.Deal(3)
+	if err != nil {
+		t.Fatalf("Deal failed: %v", err)
+	}
+	if err := bob.Receive(wrong); err != ErrInvalidShare {
+		t.Fatalf("expected ErrInvalidShare, but got %v", err)
+	}
+
+	if err := bob.Receive(deal); err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	if err := bob.Receive(deal); err != ErrDuplicateDeal {
+		t.Fatalf("expected ErrDuplicateDeal, but got %v", err)
+	}
+	if _, _, err := bob.Finish(); err != ErrMissingDeals {
+		t.Fatalf("expected ErrMissingDeals, but got %v", err)
+	}
+}
+
+func BenchmarkCombinePartialKeys(b *testing.B) {
+	shares, tp := runDKG(b, 3, 5)
+	id := []byte("test@example.com")
+	var partials []*PartialKey
+	for _, ks := range shares[:3] {
+		partials = append(partials, ks.PartialExtract(id))
+	}
+	for b.Loop() {
+		_, err := CombinePartialKeys(tp, id, partials)
+		if err != nil {
+			b.Fatalf("CombinePartialKeys failed: %v", err)
+		}
+	}
+}
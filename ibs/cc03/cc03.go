@@ -0,0 +1,100 @@
+package cc03
+
+import (
+	"errors"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/ibe/bf01"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+var (
+	ErrInvalidSignature = errors.New("cc03: invalid signature")
+	ErrLengthMismatch   = errors.New("cc03: ids, messages and signatures differ in number")
+)
+
+var h2DomainSepTag = []byte("cc03-H2")
+
+// H_1: {0,1}^* \rightarrow G_1, as in bf01
+func H1(id []byte) *bls.G1 {
+	return blspairing.HashBytesToG1(id, nil)
+}
+
+// H_2: {0,1}^* \times G_1 \rightarrow Z_q
+func H2(msg []byte, u *bls.G1) *bls.Scalar {
+	uBytes := u.BytesCompressed()
+	buf := make([]byte, 0, len(h2DomainSepTag)+len(msg)+len(uBytes))
+	buf = append(buf, h2DomainSepTag...)
+	buf = append(buf, msg...)
+	buf = append(buf, uBytes...)
+	return blspairing.HashBytesToScalar(buf)
+}
+
+// Signature is (U, V) = (r*Q_id, (r + h)*D_id), where h = H2(msg, U).
+type Signature struct {
+	U *bls.G1
+	V *bls.G1
+}
+
+// Sign signs msg with sk, the bf01 private key for id.
+func Sign(_ *bf01.PublicParams, sk *bf01.PrivateKey, id []byte, msg []byte) *Signature {
+	r := blspairing.NewRandomScalar()
+
+	u := new(bls.G1)
+	u.ScalarMult(r, H1(id))
+
+	rh := H2(msg, u)
+	rh.Add(rh, r)
+	v := new(bls.G1)
+	v.ScalarMult(rh, sk.SK)
+
+	return &Signature{
+		U: u,
+		V: v,
+	}
+}
+
+// verifyRHS returns U + H2(msg, U)*Q_id.
+func verifyRHS(id []byte, msg []byte, sig *Signature) *bls.G1 {
+	q := H1(id)
+	q.ScalarMult(H2(msg, sig.U), q)
+	q.Add(q, sig.U)
+	return q
+}
+
+// Verify checks that e(V, g2) = e(U + h*Q_id, MPK).
+func Verify(pp *bf01.PublicParams, id []byte, msg []byte, sig *Signature) error {
+	lhs := bls.Pair(sig.V, bls.G2Generator())
+	rhs := bls.Pair(verifyRHS(id, msg, sig), pp.MPK)
+	if !lhs.IsEqual(rhs) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// BatchVerify verifies sigs[i] on msgs[i] by ids[i], for all i.  It weights
+// each signature's verification equation by a random scalar d_i, and checks
+// e(sum d_i*V_i, g2) = e(sum d_i*(U_i + h_i*Q_i), MPK).  It returns
+// [ErrInvalidSignature] if any signature is invalid (except with negligible
+// probability), but does not say which.
+func BatchVerify(pp *bf01.PublicParams, ids [][]byte, msgs [][]byte, sigs []*Signature) error {
+	if len(ids) != len(msgs) || len(ids) != len(sigs) {
+		return ErrLengthMismatch
+	}
+
+	lhs := blspairing.NewG1Identity()
+	rhs := blspairing.NewG1Identity()
+	tmp := new(bls.G1)
+	for i, sig := range sigs {
+		d := blspairing.NewRandomScalar()
+		tmp.ScalarMult(d, sig.V)
+		lhs.Add(lhs, tmp)
+		tmp.ScalarMult(d, verifyRHS(ids[i], msgs[i], sig))
+		rhs.Add(rhs, tmp)
+	}
+
+	if !bls.Pair(lhs, bls.G2Generator()).IsEqual(bls.Pair(rhs, pp.MPK)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
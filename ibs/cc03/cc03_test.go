@@ -0,0 +1,95 @@
+package cc03
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/etclab/ncircl/ibe/bf01"
+)
+
+func TestSignVerify(t *testing.T) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	id := []byte("alice")
+	sk := pkg.Extract(id)
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+
+	sig := Sign(pp, sk, id, msg)
+	if err := Verify(pp, id, msg, sig); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	if err := Verify(pp, id, []byte("another message"), sig); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature for another message, but got %v", err)
+	}
+	if err := Verify(pp, []byte("bob"), msg, sig); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature for another identity, but got %v", err)
+	}
+
+	// a signature under another PKG's key
+	otherPKG, _ := bf01.NewPrivateKeyGenerator()
+	forged := Sign(pp, otherPKG.Extract(id), id, msg)
+	if err := Verify(pp, id, msg, forged); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature for another PKG's key, but got %v", err)
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+
+	const n = 8
+	ids := make([][]byte, n)
+	msgs := make([][]byte, n)
+	sigs := make([]*Signature, n)
+	for i := range n {
+		ids[i] = fmt.Appendf(nil, "user%d", i%3)
+		msgs[i] = fmt.Appendf(nil, "message %d", i)
+		sigs[i] = Sign(pp, pkg.Extract(ids[i]), ids[i], msgs[i])
+	}
+
+	if err := BatchVerify(pp, ids, msgs, sigs); err != nil {
+		t.Fatalf("BatchVerify failed: %v", err)
+	}
+	if err := BatchVerify(pp, nil, nil, nil); err != nil {
+		t.Fatalf("BatchVerify failed on an empty batch: %v", err)
+	}
+	if err := BatchVerify(pp, ids, msgs[1:], sigs); err != ErrLengthMismatch {
+		t.Fatalf("expected ErrLengthMismatch, but got %v", err)
+	}
+
+	// swapping two signatures keeps every component valid, but breaks the
+	// binding to the messages
+	sigs[0], sigs[1] = sigs[1], sigs[0]
+	if err := BatchVerify(pp, ids, msgs, sigs); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature, but got %v", err)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	id := []byte("test@example.com")
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	sig := Sign(pp, pkg.Extract(id), id, msg)
+	for b.Loop() {
+		if err := Verify(pp, id, msg, sig); err != nil {
+			b.Fatalf("Verify failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	const n = 16
+	ids := make([][]byte, n)
+	msgs := make([][]byte, n)
+	sigs := make([]*Signature, n)
+	for i := range n {
+		ids[i] = fmt.Appendf(nil, "user%d", i)
+		msgs[i] = fmt.Appendf(nil, "message %d", i)
+		sigs[i] = Sign(pp, pkg.Extract(ids[i]), ids[i], msgs[i])
+	}
+	for b.Loop() {
+		if err := BatchVerify(pp, ids, msgs, sigs); err != nil {
+			b.Fatalf("BatchVerify failed: %v", err)
+		}
+	}
+}
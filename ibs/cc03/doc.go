@@ -0,0 +1,25 @@
+// Package cc03 implements the identity-based signature scheme from the
+// [paper]:
+//
+//	@inproceedings{03-pkc-ibs_gap_diffie_hellman,
+//	    title = {An Identity-Based Signature from Gap {Diffie-Hellman} Groups},
+//	    author = {Cha, Jae Choon and Cheon, Jung Hee},
+//	    booktitle = {International Workshop on Public Key Cryptography (PKC)},
+//	    year = {2003},
+//	}
+//
+// Section 3 of that paper describes the scheme.  Its private keys have the
+// same form, s*H(id), as those of the Boneh-Franklin IBE, so the package
+// signs with the private keys that a [bf01.PrivateKeyGenerator] extracts,
+// and verifies against the same [bf01.PublicParams]: one PKG provides both
+// encryption and signatures.
+//
+// # Changes from Paper
+// The paper assumes a symmetric pairing.  On BLS12-381, identities hash to
+// G1 (as in bf01), both signature components are in G1, and the master
+// public key is in G2.  The package adds batch verification (see
+// [BatchVerify]), which checks any number of signatures, by any signers, with
+// two pairings.
+//
+// [paper]: https://link.springer.com/chapter/10.1007/3-540-36288-6_2
+package cc03
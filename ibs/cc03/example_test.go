@@ -0,0 +1,26 @@
+package cc03_test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/etclab/ncircl/ibe/bf01"
+	"github.com/etclab/ncircl/ibs/cc03"
+)
+
+// Example shows one bf01 private key used both to decrypt and to sign.
+func Example() {
+	aliceID := []byte("alice@example.com")
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	aliceSK := pkg.Extract(aliceID)
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	ct := bf01.Encrypt(pp, aliceID, msg)
+	fmt.Println(bytes.Equal(msg, bf01.Decrypt(pp, aliceSK, ct)))
+
+	sig := cc03.Sign(pp, aliceSK, aliceID, msg)
+	fmt.Println(cc03.Verify(pp, aliceID, msg, sig))
+	// Output:
+	// true
+	// <nil>
+}
@@ -0,0 +1,108 @@
+// Package bbgtree holds the keys for the nodes of a binary tree, realized as
+// the identities of the [bbg05] HIBE: the node at depth k whose path from the
+// root is b_1 ... b_k is the identity (b_1, ..., b_k).  The tree's depth is
+// the HIBE's MaxDepth, and its leaves are numbered by their paths, read as
+// integers with b_1 the most significant bit.
+package bbgtree
+
+import (
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/mu"
+	"github.com/etclab/ncircl/hibe/bbg05"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// MaxDepth is the maximum depth of a tree, so that leaves fit in a uint64.
+const MaxDepth = 64
+
+// Key is the HIBE key for the node with the given depth and path.
+type Key struct {
+	Depth  int
+	Prefix uint64
+	SK     *bbg05.PrivateKey
+}
+
+// Setup creates the HIBE for a tree of the given depth, which must be in
+// [1, MaxDepth], and returns the key for the root.  The HIBE master key is
+// erased before Setup returns.
+func Setup(depth int) (*bbg05.PublicParams, *Key) {
+	pp, msk := bbg05.Setup(depth)
+
+	sk, err := bbg05.KeyGen(pp, msk, NodeId(pp, 0, 0))
+	if err != nil {
+		mu.Panicf("bbg05.KeyGen failed: %v", err)
+	}
+	msk.G2toA.SetIdentity()
+
+	return pp, &Key{SK: sk}
+}
+
+func component(bit uint64) []byte {
+	return []byte{'0' + byte(bit)}
+}
+
+// Bit returns the bit of leaf's path below a node at the given depth.
+func Bit(pp *bbg05.PublicParams, leaf uint64, depth int) uint64 {
+	return (leaf >> (pp.MaxDepth - 1 - depth)) & 1
+}
+
+// NodeId returns the HIBE identity for the node.
+func NodeId(pp *bbg05.PublicParams, depth int, prefix uint64) *bbg05.Id {
+	components := make([][]byte, depth)
+	for i := range components {
+		components[i] = component((prefix >> (depth - 1 - i)) & 1)
+	}
+	id, err := bbg05.NewId(pp, components)
+	if err != nil {
+		mu.Panicf("bbg05.NewId failed: %v", err)
+	}
+	return id
+}
+
+// Contains reports whether leaf is below the node.
+func (k *Key) Contains(pp *bbg05.PublicParams, leaf uint64) bool {
+	return leaf>>(pp.MaxDepth-k.Depth) == k.Prefix
+}
+
+// Child derives the key for the node's child on the side given by bit.
+func (k *Key) Child(pp *bbg05.PublicParams, bit uint64) *Key {
+	child := &Key{
+		Depth:  k.Depth + 1,
+		Prefix: k.Prefix<<1 | bit,
+	}
+	sk, err := bbg05.KeyDer(pp, k.SK, NodeId(pp, child.Depth, child.Prefix))
+	if err != nil {
+		mu.Panicf("bbg05.KeyDer failed: %v", err)
+	}
+	child.SK = sk
+	return child
+}
+
+// Erase overwrites the group elements of the key and drops the reference to
+// them.  Go offers no way to reach copies that the runtime may have made, so
+// erasure is best effort.
+func (k *Key) Erase() {
+	k.SK.A0.SetIdentity()
+	k.SK.A1.SetIdentity()
+	for _, b := range k.SK.Bs {
+		b.SetIdentity()
+	}
+	k.SK = nil
+}
+
+// Decrypt decrypts a HIBE ciphertext for leaf, which must be below the node.
+// A leaf key only needs its A0 to decrypt, so rather than deriving the leaf's
+// key with [bbg05.KeyDer], Decrypt folds the leaf's remaining components into
+// A0 directly: A0 + sum_m I_m * B_m.
+func (k *Key) Decrypt(pp *bbg05.PublicParams, leaf uint64, ct *bbg05.Ciphertext) *bls.Gt {
+	a0 := blspairing.CloneG2(k.SK.A0)
+	var tmp bls.G2
+	for m := k.Depth; m < pp.MaxDepth; m++ {
+		tmp.ScalarMult(blspairing.HashBytesToScalar(component(Bit(pp, leaf, m))), k.SK.Bs[m-k.Depth])
+		a0.Add(a0, &tmp)
+	}
+
+	gt := bbg05.Decrypt(pp, &bbg05.PrivateKey{A0: a0, A1: k.SK.A1}, ct)
+	a0.SetIdentity()
+	return gt
+}
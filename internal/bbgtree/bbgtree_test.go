@@ -0,0 +1,37 @@
+package bbgtree
+
+import (
+	"testing"
+
+	"github.com/etclab/ncircl/hibe/bbg05"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestDecrypt(t *testing.T) {
+	pp, root := Setup(4)
+	node := root.Child(pp, 1).Child(pp, 0)
+
+	for leaf := uint64(0); leaf < 1<<pp.MaxDepth; leaf++ {
+		m := blspairing.NewRandomGt()
+		ct, err := bbg05.Encrypt(pp, NodeId(pp, pp.MaxDepth, leaf), m)
+		if err != nil {
+			t.Fatalf("Encrypt failed: %v", err)
+		}
+
+		got := node.Decrypt(pp, leaf, ct)
+		if node.Contains(pp, leaf) != got.IsEqual(m) {
+			t.Fatalf("leaf %d: expected decryption to succeed iff the node contains the leaf", leaf)
+		}
+	}
+}
+
+func TestErase(t *testing.T) {
+	pp, root := Setup(2)
+	child := root.Child(pp, 0)
+	a0 := child.SK.A0
+
+	child.Erase()
+	if child.SK != nil || !a0.IsIdentity() {
+		t.Fatalf("key was not erased")
+	}
+}
@@ -0,0 +1,167 @@
+package gm15
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/etclab/mu"
+	"github.com/etclab/ncircl/hibe/bbg05"
+	"github.com/etclab/ncircl/util/aesx"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// Bloom filter encryption uses the HIBE with depth 1, as an IBE whose
+// identities are the bit positions of the filter.
+
+var ErrInvalidBloomParams = errors.New("gm15: invalid Bloom filter parameters")
+
+var bloomDomainSepTag = []byte("gm15-bloom")
+
+type BloomParams struct {
+	// M is the number of bits of the Bloom filter, and K the number of
+	// hash functions.
+	M    int
+	K    int
+	HIBE *bbg05.PublicParams
+}
+
+// Indices returns the K bit positions that tag hashes to.  The positions
+// need not be distinct.
+func (bp *BloomParams) Indices(tag []byte) []int {
+	idx := make([]int, bp.K)
+	for i := range idx {
+		h := sha256.New()
+		h.Write(bloomDomainSepTag)
+		h.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+		h.Write(tag)
+		idx[i] = int(binary.BigEndian.Uint64(h.Sum(nil)) % uint64(bp.M))
+	}
+	return idx
+}
+
+// FalsePositiveProbability returns the approximate probability that, after
+// n punctures, a ciphertext with a tag that was not punctured cannot be
+// decrypted: (1 - e^(-kn/m))^k.
+func (bp *BloomParams) FalsePositiveProbability(n int) float64 {
+	return math.Pow(1-math.Exp(-float64(bp.K*n)/float64(bp.M)), float64(bp.K))
+}
+
+func bloomId(bp *BloomParams, i int) *bbg05.Id {
+	id, err := bbg05.NewId(bp.HIBE, [][]byte{binary.BigEndian.AppendUint32(nil, uint32(i))})
+	if err != nil {
+		mu.Panicf("bbg05.NewId failed: %v", err)
+	}
+	return id
+}
+
+// BloomSecretKey holds a HIBE key for each bit of the Bloom filter that is
+// not set; keys[i] is nil once bit i is set.
+type BloomSecretKey struct {
+	keys []*bbg05.PrivateKey
+}
+
+// Size returns the number of HIBE keys that the key holds.
+func (sk *BloomSecretKey) Size() int {
+	n := 0
+	for _, k := range sk.keys {
+		if k != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// BloomKeyGen creates the public parameters and a secret key for a Bloom
+// filter with m bits and k hash functions.  Key generation extracts m HIBE
+// keys; the HIBE master key is then erased.
+func BloomKeyGen(m, k int) (*BloomParams, *BloomSecretKey, error) {
+	if m < 1 || k < 1 || k > m {
+		return nil, nil, ErrInvalidBloomParams
+	}
+
+	bp := &BloomParams{M: m, K: k}
+	hibePP, msk := bbg05.Setup(1)
+	bp.HIBE = hibePP
+
+	sk := &BloomSecretKey{keys: make([]*bbg05.PrivateKey, m)}
+	for i := range sk.keys {
+		key, err := bbg05.KeyGen(bp.HIBE, msk, bloomId(bp, i))
+		if err != nil {
+			mu.Panicf("bbg05.KeyGen failed: %v", err)
+		}
+		sk.keys[i] = key
+	}
+	msk.G2toA.SetIdentity()
+
+	return bp, sk, nil
+}
+
+// Puncture erases the keys for the bits that tag hashes to.  It takes time
+// that depends only on K.
+func (sk *BloomSecretKey) Puncture(bp *BloomParams, tag []byte) {
+	for _, i := range bp.Indices(tag) {
+		key := sk.keys[i]
+		if key == nil {
+			continue
+		}
+		// a key for the HIBE's last level has no Bs
+		key.A0.SetIdentity()
+		key.A1.SetIdentity()
+		sk.keys[i] = nil
+	}
+}
+
+// BloomCiphertext encrypts the same random Gt to each of the tag's bits.
+// Headers[j] is for the bit Indices(Tag)[j].
+type BloomCiphertext struct {
+	Tag     []byte
+	Headers []*bbg05.Ciphertext
+	Payload []byte
+}
+
+func EncryptBloom(bp *BloomParams, tag []byte, msg []byte) (*BloomCiphertext, error) {
+	gt := blspairing.NewRandomGt()
+
+	ct := &BloomCiphertext{
+		Tag:     append([]byte(nil), tag...),
+		Headers: make([]*bbg05.Ciphertext, bp.K),
+	}
+	for j, i := range bp.Indices(tag) {
+		header, err := bbg05.Encrypt(bp.HIBE, bloomId(bp, i), gt)
+		if err != nil {
+			return nil, err
+		}
+		ct.Headers[j] = header
+	}
+
+	var err error
+	ct.Payload, err = aesx.EncryptGCM(blspairing.KdfGtToAes256(gt), msg, headerAD(ct.Tag, ct.Headers...))
+	if err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+// Decrypt uses the key for any of the ciphertext's bits that is not set,
+// and returns [ErrPunctured] if all of them are.
+func (sk *BloomSecretKey) Decrypt(bp *BloomParams, ct *BloomCiphertext) ([]byte, error) {
+	if len(ct.Headers) != bp.K {
+		return nil, ErrInvalidPayload
+	}
+
+	for j, i := range bp.Indices(ct.Tag) {
+		key := sk.keys[i]
+		if key == nil {
+			continue
+		}
+		gt := bbg05.Decrypt(bp.HIBE, key, ct.Headers[j])
+		msg, err := aesx.DecryptGCM(blspairing.KdfGtToAes256(gt), ct.Payload, headerAD(ct.Tag, ct.Headers...))
+		if err != nil {
+			return nil, ErrInvalidPayload
+		}
+		return msg, nil
+	}
+	return nil, ErrPunctured
+}
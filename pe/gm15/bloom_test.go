@@ -0,0 +1,112 @@
+package gm15
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestBloomPuncture(t *testing.T) {
+	bp, sk, err := BloomKeyGen(64, 3)
+	if err != nil {
+		t.Fatalf("BloomKeyGen failed: %v", err)
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	tags := make([][]byte, 4)
+	cts := make([]*BloomCiphertext, len(tags))
+	for i := range tags {
+		tags[i] = fmt.Appendf(nil, "tag-%d", i)
+		cts[i], err = EncryptBloom(bp, tags[i], msg)
+		if err != nil {
+			t.Fatalf("EncryptBloom failed: %v", err)
+		}
+	}
+
+	set := make(map[int]bool)
+	for i := range tags {
+		got, err := sk.Decrypt(bp, cts[i])
+		if err != nil {
+			t.Fatalf("Decrypt failed: %v", err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Fatalf("decryption of tag %d failed", i)
+		}
+
+		sk.Puncture(bp, tags[i])
+		for _, x := range bp.Indices(tags[i]) {
+			set[x] = true
+		}
+		if sk.Size() != bp.M-len(set) {
+			t.Fatalf("expected %d keys, but got %d", bp.M-len(set), sk.Size())
+		}
+
+		for j, ct := range cts {
+			_, err := sk.Decrypt(bp, ct)
+			punctured := !slices.ContainsFunc(bp.Indices(tags[j]), func(x int) bool { return !set[x] })
+			if punctured && err != ErrPunctured {
+				t.Fatalf("after puncturing tag %d: expected ErrPunctured for tag %d, but got %v", i, j, err)
+			}
+			if !punctured && err != nil {
+				t.Fatalf("after puncturing tag %d: Decrypt of tag %d failed: %v", i, j, err)
+			}
+		}
+	}
+}
+
+func TestBloomInvalidParams(t *testing.T) {
+	for _, p := range [][2]int{{0, 1}, {8, 0}, {2, 3}} {
+		if _, _, err := BloomKeyGen(p[0], p[1]); err != ErrInvalidBloomParams {
+			t.Fatalf("m=%d, k=%d: expected ErrInvalidBloomParams, but got %v", p[0], p[1], err)
+		}
+	}
+}
+
+func TestBloomTamperedCiphertext(t *testing.T) {
+	bp, sk, err := BloomKeyGen(16, 2)
+	if err != nil {
+		t.Fatalf("BloomKeyGen failed: %v", err)
+	}
+
+	ct, err := EncryptBloom(bp, []byte("tag"), []byte("message"))
+	if err != nil {
+		t.Fatalf("EncryptBloom failed: %v", err)
+	}
+	ct.Headers = ct.Headers[:1]
+	if _, err := sk.Decrypt(bp, ct); err != ErrInvalidPayload {
+		t.Fatalf("expected ErrInvalidPayload for missing header, but got %v", err)
+	}
+
+	ct, err = EncryptBloom(bp, []byte("tag"), []byte("message"))
+	if err != nil {
+		t.Fatalf("EncryptBloom failed: %v", err)
+	}
+	ct.Payload[0] ^= 1
+	if _, err := sk.Decrypt(bp, ct); err != ErrInvalidPayload {
+		t.Fatalf("expected ErrInvalidPayload, but got %v", err)
+	}
+}
+
+func TestFalsePositiveProbability(t *testing.T) {
+	bp := &BloomParams{M: 1 << 20, K: 10}
+	if p := bp.FalsePositiveProbability(0); p != 0 {
+		t.Fatalf("expected 0 before any puncture, but got %v", p)
+	}
+	if p := bp.FalsePositiveProbability(1 << 16); p > 1e-3 {
+		t.Fatalf("expected a small probability, but got %v", p)
+	}
+}
+
+func BenchmarkBloomPuncture(b *testing.B) {
+	bp, sk, err := BloomKeyGen(1024, 5)
+	if err != nil {
+		b.Fatalf("BloomKeyGen failed: %v", err)
+	}
+
+	i := 0
+	for b.Loop() {
+		sk.Puncture(bp, fmt.Appendf(nil, "tag-%d", i))
+		i++
+	}
+}
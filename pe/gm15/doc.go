@@ -0,0 +1,52 @@
+// Package gm15 implements puncturable encryption, as defined in the [paper]:
+//
+//	@inproceedings{15-sp-puncturable_encryption,
+//	    title = {Forward Secure Asynchronous Messaging from Puncturable Encryption},
+//	    author = {Green, Matthew D. and Miers, Ian},
+//	    booktitle = {IEEE Symposium on Security and Privacy},
+//	    year = {2015},
+//	}
+//
+// A sender encrypts a message under a tag.  After the receiver decrypts a
+// ciphertext, it punctures its secret key on the ciphertext's tag, so that
+// the key can never decrypt a ciphertext with that tag again: an adversary
+// who later steals the key learns nothing about messages that were already
+// received.  This gives forward secrecy to protocols, such as 0-RTT key
+// exchange, in which the receiver's public key cannot change with each
+// message.
+//
+// # Changes from Paper
+// The paper builds puncturable encryption from a variant of attribute-based
+// encryption.  This package instead uses the [bbg05] HIBE, in two ways.
+//
+// [KeyGen] follows the construction of Günther, Hale, Jager and Lauer
+// (EUROCRYPT 2017): tags hash to the leaves of a binary tree of depth d, and
+// the node with path b_1 ... b_k is the HIBE identity (b_1, ..., b_k).  The
+// secret key holds the HIBE keys for a set of nodes that covers exactly the
+// unpunctured leaves.  [SecretKey.Puncture] replaces the node that covers a
+// tag's leaf with the siblings of the path from that node to the leaf, so
+// each puncture adds at most d keys and costs O(d) key derivations.
+//
+// [BloomKeyGen] is the Bloom filter encryption of Derler, Jager, Slamanig and
+// Striecks (EUROCRYPT 2018): the secret key holds one HIBE key for each of
+// the m bits of a Bloom filter, a ciphertext encrypts to the k bits that its
+// tag hashes to, and [BloomSecretKey.Puncture] simply erases the keys for
+// those bits.  Puncturing thus takes constant time and shrinks the key, but
+// a ciphertext whose tag was never punctured cannot be decrypted if all of
+// its bits were erased by other punctures; see
+// [BloomParams.FalsePositiveProbability].
+//
+// Two tags that hash to the same leaf are punctured together, so the tree
+// depth trades key size against the probability that puncturing one tag
+// prevents the decryption of another.  This affects only correctness: a key
+// that was punctured on a tag never decrypts that tag.
+//
+// Messages are arbitrary bytes: the HIBE encapsulates a random Gt, from which
+// the AES-256-GCM key for the payload is derived.
+//
+// Forward secrecy rests on the keys that puncturing discards being gone.
+// Both kinds of key overwrite them in place, which protects against a later
+// theft of the key but not against copies that the Go runtime made earlier.
+//
+// [paper]: https://isi.jhu.edu/~mgreen/forward_sec.pdf
+package gm15
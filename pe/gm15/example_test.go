@@ -0,0 +1,58 @@
+package gm15_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/etclab/ncircl/pe/gm15"
+)
+
+// Example shows how a receiver punctures its key after decrypting a message,
+// so that a key that is stolen later cannot decrypt that message.
+func Example() {
+	pp, sk, err := gm15.KeyGen(16)
+	if err != nil {
+		log.Fatalf("failed to generate key: %v", err)
+	}
+
+	ct, err := gm15.Encrypt(pp, []byte("session-1"), []byte("0-RTT data"))
+	if err != nil {
+		log.Fatalf("failed to encrypt: %v", err)
+	}
+
+	got, err := sk.Decrypt(pp, ct)
+	fmt.Println(string(got), err)
+
+	sk.Puncture(pp, ct.Tag)
+
+	_, err = sk.Decrypt(pp, ct)
+	fmt.Println(err)
+	// Output:
+	// 0-RTT data <nil>
+	// gm15: key is punctured on the ciphertext's tag
+}
+
+// Example_bloom shows Bloom filter encryption, in which puncturing erases a
+// constant number of keys.
+func Example_bloom() {
+	bp, sk, err := gm15.BloomKeyGen(128, 3)
+	if err != nil {
+		log.Fatalf("failed to generate key: %v", err)
+	}
+
+	ct, err := gm15.EncryptBloom(bp, []byte("session-1"), []byte("0-RTT data"))
+	if err != nil {
+		log.Fatalf("failed to encrypt: %v", err)
+	}
+
+	got, err := sk.Decrypt(bp, ct)
+	fmt.Println(string(got), err)
+
+	sk.Puncture(bp, ct.Tag)
+
+	_, err = sk.Decrypt(bp, ct)
+	fmt.Println(err)
+	// Output:
+	// 0-RTT data <nil>
+	// gm15: key is punctured on the ciphertext's tag
+}
@@ -0,0 +1,147 @@
+package gm15
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/etclab/ncircl/hibe/bbg05"
+	"github.com/etclab/ncircl/internal/bbgtree"
+	"github.com/etclab/ncircl/util/aesx"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// MaxDepth is the maximum depth of the tree of tags.
+const MaxDepth = bbgtree.MaxDepth
+
+var (
+	ErrInvalidDepth   = errors.New("gm15: invalid tree depth")
+	ErrPunctured      = errors.New("gm15: key is punctured on the ciphertext's tag")
+	ErrInvalidPayload = errors.New("gm15: invalid payload")
+)
+
+var tagDomainSepTag = []byte("gm15-tag")
+
+type PublicParams struct {
+	// Depth is the depth of the tree of tags.
+	Depth int
+	HIBE  *bbg05.PublicParams
+}
+
+// TagLeaf returns the leaf that tag hashes to.
+func (pp *PublicParams) TagLeaf(tag []byte) uint64 {
+	h := sha256.New()
+	h.Write(tagDomainSepTag)
+	h.Write(tag)
+	return binary.BigEndian.Uint64(h.Sum(nil)) >> (64 - pp.Depth)
+}
+
+// SecretKey is the receiver's key.  It holds the HIBE keys for disjoint
+// nodes that together cover the unpunctured leaves.
+type SecretKey struct {
+	nodes []*bbgtree.Key
+}
+
+// Size returns the number of HIBE keys that the key holds.
+func (sk *SecretKey) Size() int {
+	return len(sk.nodes)
+}
+
+// KeyGen creates the public parameters and a secret key for a tree of the
+// given depth.  The key starts out as the keys for the root's two children,
+// which together cover every tag.
+func KeyGen(depth int) (*PublicParams, *SecretKey, error) {
+	if depth < 1 || depth > MaxDepth {
+		return nil, nil, ErrInvalidDepth
+	}
+
+	hibePP, root := bbgtree.Setup(depth)
+	pp := &PublicParams{Depth: depth, HIBE: hibePP}
+
+	sk := &SecretKey{nodes: []*bbgtree.Key{root.Child(pp.HIBE, 0), root.Child(pp.HIBE, 1)}}
+	root.Erase()
+	return pp, sk, nil
+}
+
+func (sk *SecretKey) find(pp *PublicParams, leaf uint64) int {
+	for i, nk := range sk.nodes {
+		if nk.Contains(pp.HIBE, leaf) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Puncture removes the key's ability to decrypt ciphertexts with the tag.
+// Puncturing a tag twice has no further effect.
+func (sk *SecretKey) Puncture(pp *PublicParams, tag []byte) {
+	leaf := pp.TagLeaf(tag)
+	i := sk.find(pp, leaf)
+	if i < 0 {
+		return
+	}
+
+	node := sk.nodes[i]
+	sk.nodes = append(sk.nodes[:i], sk.nodes[i+1:]...)
+
+	for node.Depth < pp.Depth {
+		bit := bbgtree.Bit(pp.HIBE, leaf, node.Depth)
+		sk.nodes = append(sk.nodes, node.Child(pp.HIBE, bit^1))
+		next := node.Child(pp.HIBE, bit)
+		node.Erase()
+		node = next
+	}
+	node.Erase()
+}
+
+type Ciphertext struct {
+	Tag     []byte
+	Header  *bbg05.Ciphertext
+	Payload []byte
+}
+
+func headerAD(tag []byte, headers ...*bbg05.Ciphertext) []byte {
+	m := binary.BigEndian.AppendUint32(nil, uint32(len(tag)))
+	m = append(m, tag...)
+	for _, h := range headers {
+		m = append(m, blspairing.GtToBytes(h.A)...)
+		m = append(m, h.B.Bytes()...)
+		m = append(m, h.C.Bytes()...)
+	}
+	return m
+}
+
+func Encrypt(pp *PublicParams, tag []byte, msg []byte) (*Ciphertext, error) {
+	gt := blspairing.NewRandomGt()
+	header, err := bbg05.Encrypt(pp.HIBE, bbgtree.NodeId(pp.HIBE, pp.Depth, pp.TagLeaf(tag)), gt)
+	if err != nil {
+		return nil, err
+	}
+
+	ct := &Ciphertext{
+		Tag:    append([]byte(nil), tag...),
+		Header: header,
+	}
+	ct.Payload, err = aesx.EncryptGCM(blspairing.KdfGtToAes256(gt), msg, headerAD(ct.Tag, header))
+	if err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+// Decrypt returns [ErrPunctured] if the key was punctured on the
+// ciphertext's tag.
+func (sk *SecretKey) Decrypt(pp *PublicParams, ct *Ciphertext) ([]byte, error) {
+	leaf := pp.TagLeaf(ct.Tag)
+	i := sk.find(pp, leaf)
+	if i < 0 {
+		return nil, ErrPunctured
+	}
+	gt := sk.nodes[i].Decrypt(pp.HIBE, leaf, ct.Header)
+
+	msg, err := aesx.DecryptGCM(blspairing.KdfGtToAes256(gt), ct.Payload, headerAD(ct.Tag, ct.Header))
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	return msg, nil
+}
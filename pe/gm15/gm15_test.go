@@ -0,0 +1,126 @@
+package gm15
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestPuncture(t *testing.T) {
+	pp, sk, err := KeyGen(8)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	tags := make([][]byte, 4)
+	cts := make([]*Ciphertext, len(tags))
+	for i := range tags {
+		tags[i] = fmt.Appendf(nil, "tag-%d", i)
+		cts[i], err = Encrypt(pp, tags[i], msg)
+		if err != nil {
+			t.Fatalf("Encrypt failed: %v", err)
+		}
+	}
+
+	for i := range tags {
+		got, err := sk.Decrypt(pp, cts[i])
+		if err != nil {
+			t.Fatalf("Decrypt failed: %v", err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Fatalf("decryption of tag %d failed", i)
+		}
+
+		sk.Puncture(pp, tags[i])
+		if sk.Size() > 2+(i+1)*pp.Depth {
+			t.Fatalf("expected at most %d keys, but got %d", 2+(i+1)*pp.Depth, sk.Size())
+		}
+
+		for j, ct := range cts {
+			_, err := sk.Decrypt(pp, ct)
+			punctured := j <= i || pp.TagLeaf(tags[j]) == pp.TagLeaf(tags[i])
+			if punctured && err != ErrPunctured {
+				t.Fatalf("after puncturing tag %d: expected ErrPunctured for tag %d, but got %v", i, j, err)
+			}
+			if !punctured && err != nil {
+				t.Fatalf("after puncturing tag %d: Decrypt of tag %d failed: %v", i, j, err)
+			}
+		}
+	}
+
+	// Puncturing a tag again has no effect.
+	n := sk.Size()
+	sk.Puncture(pp, tags[0])
+	if sk.Size() != n {
+		t.Fatalf("expected %d keys, but got %d", n, sk.Size())
+	}
+}
+
+// TestCover checks that after punctures the key's nodes are disjoint and
+// cover exactly the unpunctured leaves.
+func TestCover(t *testing.T) {
+	pp, sk, err := KeyGen(4)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	punctured := make(map[uint64]bool)
+	for i := range 6 {
+		tag := fmt.Appendf(nil, "tag-%d", i)
+		sk.Puncture(pp, tag)
+		punctured[pp.TagLeaf(tag)] = true
+	}
+
+	for leaf := uint64(0); leaf < 1<<pp.Depth; leaf++ {
+		n := 0
+		for _, nk := range sk.nodes {
+			if nk.Contains(pp.HIBE, leaf) {
+				n++
+			}
+		}
+		if punctured[leaf] && n != 0 {
+			t.Fatalf("punctured leaf %d is covered by %d nodes", leaf, n)
+		}
+		if !punctured[leaf] && n != 1 {
+			t.Fatalf("leaf %d is covered by %d nodes", leaf, n)
+		}
+	}
+}
+
+func TestInvalidDepth(t *testing.T) {
+	for _, depth := range []int{0, MaxDepth + 1} {
+		if _, _, err := KeyGen(depth); err != ErrInvalidDepth {
+			t.Fatalf("depth %d: expected ErrInvalidDepth, but got %v", depth, err)
+		}
+	}
+}
+
+func TestTamperedCiphertext(t *testing.T) {
+	pp, sk, err := KeyGen(4)
+	if err != nil {
+		t.Fatalf("KeyGen failed: %v", err)
+	}
+
+	ct, err := Encrypt(pp, []byte("tag"), []byte("message"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	ct.Payload[0] ^= 1
+	if _, err := sk.Decrypt(pp, ct); err != ErrInvalidPayload {
+		t.Fatalf("expected ErrInvalidPayload, but got %v", err)
+	}
+}
+
+func BenchmarkPuncture(b *testing.B) {
+	pp, sk, err := KeyGen(32)
+	if err != nil {
+		b.Fatalf("KeyGen failed: %v", err)
+	}
+
+	i := 0
+	for b.Loop() {
+		sk.Puncture(pp, fmt.Appendf(nil, "tag-%d", i))
+		i++
+	}
+}
@@ -0,0 +1,114 @@
+package afgh05
+
+import (
+	"bytes"
+	"errors"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// This file implements conditional (type-based) proxy re-encryption on top of
+// the basic scheme.  A ciphertext is encrypted under a condition w, such as
+// "project=alpha", and a re-encryption key only converts ciphertexts with that
+// condition.
+//
+// Encrypting under w is the basic scheme's encryption to the derived public
+// key g1^(a + H(w)), which anyone can compute from Alice's g1^a.  Alice
+// decrypts with (a + H(w))^-1, and her re-encryption key for Bob under w is
+// (g2^b)^(1/(a + H(w))).  Re-encryption yields an ordinary [Ciphertext2],
+// which Bob decrypts with [Decrypt2].  A key for w applied to a ciphertext
+// under w' != w yields Z^(r*b*(a + H(w'))/(a + H(w))), from which Bob cannot
+// remove the unknown a.
+
+var (
+	ErrConditionMismatch = errors.New("afgh05: ciphertext condition does not match re-encryption key condition")
+)
+
+var conditionPrefix = []byte("afgh05-condition")
+
+func conditionToScalar(cond []byte) *bls.Scalar {
+	buf := make([]byte, 0, len(conditionPrefix)+len(cond))
+	buf = append(buf, conditionPrefix...)
+	buf = append(buf, cond...)
+	return blspairing.HashBytesToScalar(buf)
+}
+
+// conditionalExponent returns a + H(cond).
+func conditionalExponent(sk *PrivateKey, cond []byte) *bls.Scalar {
+	z := conditionToScalar(cond)
+	z.Add(z, sk.A)
+	return z
+}
+
+type CondReEncryptionKey struct {
+	Condition []byte
+	RK        *bls.G2
+}
+
+func CondReEncryptionKeyGen(_ *PublicParams, aliceSK *PrivateKey, bobPK *PublicKey, cond []byte) *CondReEncryptionKey {
+	inv := conditionalExponent(aliceSK, cond)
+	inv.Inv(inv)
+
+	rk := new(bls.G2)
+	rk.ScalarMult(inv, bobPK.G2ToA)
+
+	return &CondReEncryptionKey{
+		Condition: bytes.Clone(cond),
+		RK:        rk,
+	}
+}
+
+// CondCiphertext1 is a re-encryptable ciphertext under a condition.
+type CondCiphertext1 struct {
+	Condition []byte
+	Alpha     *bls.Gt
+	Beta      *bls.G1
+}
+
+func CondEncrypt(pp *PublicParams, pk *PublicKey, cond []byte, msg *bls.Gt) *CondCiphertext1 {
+	r := blspairing.NewRandomScalar()
+	alpha := new(bls.Gt)
+	alpha.Exp(pp.Z, r)
+	alpha.Mul(alpha, msg)
+
+	beta := new(bls.G1)
+	beta.ScalarMult(conditionToScalar(cond), pp.G1)
+	beta.Add(beta, pk.G1ToA)
+	beta.ScalarMult(r, beta)
+
+	return &CondCiphertext1{
+		Condition: bytes.Clone(cond),
+		Alpha:     alpha,
+		Beta:      beta,
+	}
+}
+
+func CondDecrypt1(pp *PublicParams, sk *PrivateKey, ct1 *CondCiphertext1) *bls.Gt {
+	inv := conditionalExponent(sk, ct1.Condition)
+	inv.Inv(inv)
+	tmp1 := new(bls.G2)
+	tmp1.ScalarMult(inv, pp.G2)
+
+	tmp2 := bls.Pair(ct1.Beta, tmp1)
+	tmp2.Inv(tmp2)
+
+	msg := new(bls.Gt)
+	msg.Mul(ct1.Alpha, tmp2)
+
+	return msg
+}
+
+// CondReEncrypt returns [ErrConditionMismatch] if the ciphertext's condition
+// differs from the key's.  As with the temporary scheme, relabeling the
+// ciphertext does not help: the key itself only works for its condition.
+func CondReEncrypt(pp *PublicParams, rk *CondReEncryptionKey, ct1 *CondCiphertext1) (*Ciphertext2, error) {
+	if !bytes.Equal(ct1.Condition, rk.Condition) {
+		return nil, ErrConditionMismatch
+	}
+
+	return &Ciphertext2{
+		Alpha: blspairing.CloneGt(ct1.Alpha),
+		Beta:  bls.Pair(ct1.Beta, rk.RK),
+	}, nil
+}
@@ -0,0 +1,67 @@
+package afgh05
+
+import (
+	"testing"
+
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestCondEncryptDecrypt1(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+
+	msg := blspairing.NewRandomGt()
+	ct1 := CondEncrypt(pp, alicePK, []byte("project=alpha"), msg)
+	got := CondDecrypt1(pp, aliceSK, ct1)
+
+	if !got.IsEqual(msg) {
+		t.Fatal("CondDecrypt1 did not produce the original message")
+	}
+}
+
+func TestCondEncryptReEncryptDecrypt2(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+
+	cond := []byte("project=alpha")
+	rk := CondReEncryptionKeyGen(pp, aliceSK, bobPK, cond)
+
+	msg := blspairing.NewRandomGt()
+	ct1 := CondEncrypt(pp, alicePK, cond, msg)
+	ct2, err := CondReEncrypt(pp, rk, ct1)
+	if err != nil {
+		t.Fatalf("CondReEncrypt failed: %v", err)
+	}
+	got := Decrypt2(pp, bobSK, ct2)
+
+	if !got.IsEqual(msg) {
+		t.Fatal("Decrypt2 did not produce the original message")
+	}
+}
+
+func TestCondReEncrypt_otherCondition(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+
+	rk := CondReEncryptionKeyGen(pp, aliceSK, bobPK, []byte("project=alpha"))
+
+	msg := blspairing.NewRandomGt()
+	ct1 := CondEncrypt(pp, alicePK, []byte("project=beta"), msg)
+	if _, err := CondReEncrypt(pp, rk, ct1); err != ErrConditionMismatch {
+		t.Fatalf("expected ErrConditionMismatch, but got %v", err)
+	}
+
+	// Relabeling the ciphertext's condition gets it past the check, but the
+	// key still does not yield the message.
+	ct1.Condition = []byte("project=alpha")
+	ct2, err := CondReEncrypt(pp, rk, ct1)
+	if err != nil {
+		t.Fatalf("CondReEncrypt failed: %v", err)
+	}
+	got := Decrypt2(pp, bobSK, ct2)
+	if got.IsEqual(msg) {
+		t.Fatal("a re-encryption key for one condition converted a ciphertext under another")
+	}
+}
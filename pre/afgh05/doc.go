@@ -19,6 +19,12 @@
 //   - single-hop (once re-encrypted, a ciphertext cannot again be re-encrypted)
 //   - CPA-secure (aka, semantically secure)
 //
+// The package also implements the paper's temporary unidirectional scheme
+// (see [TempKeyGen]), in which a re-encryption key is only valid for a single
+// epoch, and a conditional variant (see [CondEncrypt]), in which a
+// re-encryption key is only valid for ciphertexts encrypted under a given
+// condition.
+//
 // [paper]: https://www.ndss-symposium.org/wp-content/uploads/2017/09/Improved-Proxy-Re-Encryption-Schemes-with-Applications-to-Secure-Distributed-Storage-Kevin-Fu.pdf
 // [lecture notes]: https://www.cs.jhu.edu/~susan/600.641/scribes/lecture17.pdf
 package afgh05
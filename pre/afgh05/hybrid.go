@@ -0,0 +1,81 @@
+package afgh05
+
+import (
+	"errors"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/util/aesx"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+var (
+	ErrInvalidPayload = errors.New("afgh05: invalid payload")
+)
+
+// HybridCiphertext1 is a first-level (re-encryptable) ciphertext for an
+// arbitrary byte payload.  The Header encapsulates a random Gt element from
+// which the AES-256-GCM key for the Payload is derived.
+type HybridCiphertext1 struct {
+	Header  *Ciphertext1
+	Payload []byte
+}
+
+// HybridCiphertext2 is the second-level ciphertext that results from
+// re-encrypting a [HybridCiphertext1].  The Payload is unchanged.
+type HybridCiphertext2 struct {
+	Header  *Ciphertext2
+	Payload []byte
+}
+
+// The payload is bound to Alpha, which is the one component of the header
+// that re-encryption leaves intact.
+func headerAD(alpha *bls.Gt) []byte {
+	return blspairing.GtToBytes(alpha)
+}
+
+// EncryptBytes encrypts msg to pk.  The resulting ciphertext may be decrypted
+// with [Decrypt1Bytes] or re-encrypted with [ReEncryptBytes].
+func EncryptBytes(pp *PublicParams, pk *PublicKey, msg []byte) (*HybridCiphertext1, error) {
+	gt := blspairing.NewRandomGt()
+	header := Encrypt(pp, pk, gt)
+
+	payload, err := aesx.EncryptGCM(blspairing.KdfGtToAes256(gt), msg, headerAD(header.Alpha))
+	if err != nil {
+		return nil, err
+	}
+
+	return &HybridCiphertext1{
+		Header:  header,
+		Payload: payload,
+	}, nil
+}
+
+func Decrypt1Bytes(pp *PublicParams, sk *PrivateKey, ct1 *HybridCiphertext1) ([]byte, error) {
+	gt := Decrypt1(pp, sk, ct1.Header)
+	msg, err := aesx.DecryptGCM(blspairing.KdfGtToAes256(gt), ct1.Payload, headerAD(ct1.Header.Alpha))
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	return msg, nil
+}
+
+// ReEncryptBytes re-encrypts only the header; the payload is carried over as
+// is.
+func ReEncryptBytes(pp *PublicParams, rk *ReEncryptionKey, ct1 *HybridCiphertext1) *HybridCiphertext2 {
+	payload := make([]byte, len(ct1.Payload))
+	copy(payload, ct1.Payload)
+
+	return &HybridCiphertext2{
+		Header:  ReEncrypt(pp, rk, ct1.Header),
+		Payload: payload,
+	}
+}
+
+func Decrypt2Bytes(pp *PublicParams, sk *PrivateKey, ct2 *HybridCiphertext2) ([]byte, error) {
+	gt := Decrypt2(pp, sk, ct2.Header)
+	msg, err := aesx.DecryptGCM(blspairing.KdfGtToAes256(gt), ct2.Payload, headerAD(ct2.Header.Alpha))
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	return msg, nil
+}
@@ -0,0 +1,68 @@
+package afgh05
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptBytesDecrypt1Bytes(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+
+	for _, n := range []int{0, 1, 32, 1000} {
+		msg := bytes.Repeat([]byte{'a'}, n)
+		ct1, err := EncryptBytes(pp, alicePK, msg)
+		if err != nil {
+			t.Fatalf("EncryptBytes failed: %v", err)
+		}
+		got, err := Decrypt1Bytes(pp, aliceSK, ct1)
+		if err != nil {
+			t.Fatalf("Decrypt1Bytes failed: %v", err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Fatalf("Decrypt1Bytes did not produce the original %d-byte message", n)
+		}
+	}
+}
+
+func TestEncryptBytesReEncryptBytesDecrypt2Bytes(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+	rkAliceToBob := ReEncryptionKeyGen(pp, aliceSK, bobPK)
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	ct1, err := EncryptBytes(pp, alicePK, msg)
+	if err != nil {
+		t.Fatalf("EncryptBytes failed: %v", err)
+	}
+	ct2 := ReEncryptBytes(pp, rkAliceToBob, ct1)
+	got, err := Decrypt2Bytes(pp, bobSK, ct2)
+	if err != nil {
+		t.Fatalf("Decrypt2Bytes failed: %v", err)
+	}
+	if !bytes.Equal(got, msg) {
+		t.Fatal("Decrypt2Bytes did not produce the original message")
+	}
+}
+
+func TestDecryptBytes_tampered(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	_, eveSK := KeyGen(pp)
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	ct1, err := EncryptBytes(pp, alicePK, msg)
+	if err != nil {
+		t.Fatalf("EncryptBytes failed: %v", err)
+	}
+
+	if _, err := Decrypt1Bytes(pp, eveSK, ct1); err != ErrInvalidPayload {
+		t.Fatalf("expected ErrInvalidPayload for the wrong key, but got %v", err)
+	}
+
+	ct1.Payload[len(ct1.Payload)-1] ^= 1
+	if _, err := Decrypt1Bytes(pp, aliceSK, ct1); err != ErrInvalidPayload {
+		t.Fatalf("expected ErrInvalidPayload for a tampered payload, but got %v", err)
+	}
+}
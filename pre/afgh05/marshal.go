@@ -0,0 +1,115 @@
+package afgh05
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/etclab/ncircl/util/binaryx"
+)
+
+// Encodings start with a version byte and a byte for the kind of value
+// encoded; see [binaryx].  Group elements are compressed.
+const encodingVersion = 1
+
+const (
+	kindPublicKey byte = iota + 1
+	kindPrivateKey
+	kindReEncryptionKey
+	kindCiphertext1
+	kindCiphertext2
+)
+
+var ErrInvalidEncoding = errors.New("afgh05: invalid encoding")
+
+func decodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+}
+
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindPublicKey)
+	e.PutG1(pk.G1ToA)
+	e.PutG2(pk.G2ToA)
+	return e.Bytes(), nil
+}
+
+func (pk *PublicKey) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindPublicKey)
+	g1ToA := d.G1()
+	g2ToA := d.G2()
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	pk.G1ToA, pk.G2ToA = g1ToA, g2ToA
+	return nil
+}
+
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindPrivateKey)
+	e.PutScalar(sk.A)
+	return e.Bytes(), nil
+}
+
+func (sk *PrivateKey) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindPrivateKey)
+	a := d.NonZeroScalar()
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	sk.A = a
+	return nil
+}
+
+func (rk *ReEncryptionKey) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindReEncryptionKey)
+	e.PutG2(rk.RK)
+	return e.Bytes(), nil
+}
+
+func (rk *ReEncryptionKey) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindReEncryptionKey)
+	g := d.G2()
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	rk.RK = g
+	return nil
+}
+
+func (ct *Ciphertext1) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindCiphertext1)
+	e.PutGt(ct.Alpha)
+	e.PutG1(ct.Beta)
+	return e.Bytes(), nil
+}
+
+func (ct *Ciphertext1) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindCiphertext1)
+	alpha := d.Gt()
+	beta := d.G1()
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	ct.Alpha, ct.Beta = alpha, beta
+	return nil
+}
+
+func (ct *Ciphertext2) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindCiphertext2)
+	e.PutGt(ct.Alpha)
+	e.PutGt(ct.Beta)
+	return e.Bytes(), nil
+}
+
+func (ct *Ciphertext2) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindCiphertext2)
+	alpha := d.Gt()
+	beta := d.Gt()
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	ct.Alpha, ct.Beta = alpha, beta
+	return nil
+}
@@ -0,0 +1,111 @@
+package afgh05
+
+import (
+	"encoding"
+	"errors"
+	"testing"
+
+	"github.com/etclab/ncircl/util/binaryx"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+	rk := ReEncryptionKeyGen(pp, aliceSK, bobPK)
+
+	msg := blspairing.NewRandomGt()
+	ct1 := Encrypt(pp, alicePK, msg)
+	ct2 := ReEncrypt(pp, rk, ct1)
+
+	roundTrip := func(t *testing.T, in encoding.BinaryMarshaler, out encoding.BinaryUnmarshaler) {
+		data, err := in.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary failed: %v", err)
+		}
+		if err := out.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+	}
+
+	gotPK := new(PublicKey)
+	roundTrip(t, bobPK, gotPK)
+	if !gotPK.G1ToA.IsEqual(bobPK.G1ToA) || !gotPK.G2ToA.IsEqual(bobPK.G2ToA) {
+		t.Fatal("PublicKey did not round-trip")
+	}
+
+	gotSK := new(PrivateKey)
+	roundTrip(t, bobSK, gotSK)
+	if gotSK.A.IsEqual(bobSK.A) != 1 {
+		t.Fatal("PrivateKey did not round-trip")
+	}
+
+	gotRK := new(ReEncryptionKey)
+	roundTrip(t, rk, gotRK)
+	if !gotRK.RK.IsEqual(rk.RK) {
+		t.Fatal("ReEncryptionKey did not round-trip")
+	}
+
+	gotCT1 := new(Ciphertext1)
+	roundTrip(t, ct1, gotCT1)
+	if !Decrypt1(pp, aliceSK, gotCT1).IsEqual(msg) {
+		t.Fatal("Ciphertext1 did not round-trip")
+	}
+
+	gotCT2 := new(Ciphertext2)
+	roundTrip(t, ct2, gotCT2)
+	if !Decrypt2(pp, bobSK, gotCT2).IsEqual(msg) {
+		t.Fatal("Ciphertext2 did not round-trip")
+	}
+}
+
+func TestUnmarshal_strict(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, _ := KeyGen(pp)
+	ct1 := Encrypt(pp, alicePK, blspairing.NewRandomGt())
+
+	data, err := ct1.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	pkData, err := alicePK.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	mutate := func(f func(b []byte) []byte) []byte {
+		b := make([]byte, len(data))
+		copy(b, data)
+		return f(b)
+	}
+
+	trials := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"empty", nil, binaryx.ErrTruncated},
+		{"truncated", data[:len(data)-1], binaryx.ErrTruncated},
+		{"trailing", append(mutate(func(b []byte) []byte { return b }), 0), binaryx.ErrTrailingData},
+		{"version", mutate(func(b []byte) []byte { b[0]++; return b }), binaryx.ErrInvalidHeader},
+		{"kind", pkData, binaryx.ErrInvalidHeader},
+		{"Alpha", mutate(func(b []byte) []byte { b[binaryx.HeaderSize+1] ^= 1; return b }), binaryx.ErrInvalidElement},
+		{"Beta", mutate(func(b []byte) []byte { b[len(b)-1] ^= 1; return b }), binaryx.ErrInvalidElement},
+	}
+
+	for _, trial := range trials {
+		t.Run(trial.name, func(t *testing.T) {
+			err := new(Ciphertext1).UnmarshalBinary(trial.data)
+			if !errors.Is(err, ErrInvalidEncoding) || !errors.Is(err, trial.err) {
+				t.Fatalf("expected %v, but got %v", trial.err, err)
+			}
+		})
+	}
+
+	zero := make([]byte, binaryx.HeaderSize+32)
+	zero[0], zero[1] = encodingVersion, kindPrivateKey
+	if err := new(PrivateKey).UnmarshalBinary(zero); !errors.Is(err, binaryx.ErrInvalidElement) {
+		t.Fatalf("expected ErrInvalidElement for a zero private key, but got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package afgh05
+
+import (
+	"encoding/binary"
+	"errors"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// This file implements the "Temporary Unidirectional Proxy Re-encryption"
+// variant from Section 3.2 of the [paper].  A re-encryption key is valid only
+// for the time period (epoch) for which it was generated; once the epoch
+// changes, the proxy's key no longer converts new ciphertexts, without the
+// proxy having to delete anything.
+//
+// The paper has a trusted server broadcast a random h_i for each period i.
+// Here, h_i is instead derived by hashing the epoch number onto G2 (see
+// [EpochBase]), so that no party knows its discrete log and no server is
+// needed.
+//
+// In the paper's notation, a user holds sk = (a0, ar) and publishes
+// pk = (Z^a0, g^ar).  A ciphertext for epoch i is (g^k, m*e(g^ar, h_i)^k).
+// To accept delegations for epoch i, Bob publishes the [EpochKey] h_i^b0.
+// Alice's re-encryption key for Bob and epoch i is (h_i^b0)^ar, and the
+// proxy re-encrypts by pairing it with g^k.  Bob removes b0 from the result
+// to recover the mask e(g^ar, h_i)^k.
+//
+// The paper's first-level encryption, (Z^(a0*k), m*Z^k), uses the Z^a0 half
+// of the public key: such a ciphertext cannot be re-encrypted, and has the
+// same form as a re-encrypted one (see [TempEncrypt2]).
+
+var (
+	ErrEpochMismatch = errors.New("afgh05: ciphertext epoch does not match re-encryption key epoch")
+)
+
+var epochDomainSepTag = []byte("afgh05-temporary-epoch")
+
+// EpochBase returns h_i, the per-epoch base element for epoch i.
+func EpochBase(epoch uint64) *bls.G2 {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], epoch)
+	return blspairing.HashBytesToG2(buf[:], epochDomainSepTag)
+}
+
+type TempPublicKey struct {
+	ZToA0  *bls.Gt
+	G1ToAR *bls.G1
+}
+
+type TempPrivateKey struct {
+	A0 *bls.Scalar
+	AR *bls.Scalar
+}
+
+func TempKeyGen(pp *PublicParams) (*TempPublicKey, *TempPrivateKey) {
+	sk := new(TempPrivateKey)
+	sk.A0 = blspairing.NewRandomScalar()
+	sk.AR = blspairing.NewRandomScalar()
+
+	pk := new(TempPublicKey)
+	pk.ZToA0 = new(bls.Gt)
+	pk.ZToA0.Exp(pp.Z, sk.A0)
+	pk.G1ToAR = new(bls.G1)
+	pk.G1ToAR.ScalarMult(sk.AR, pp.G1)
+
+	return pk, sk
+}
+
+// EpochKey is the temporary public key, h_i^b0, that a delegatee publishes
+// in order to receive delegations during epoch i.
+type EpochKey struct {
+	Epoch uint64
+	H     *bls.G2
+}
+
+func (sk *TempPrivateKey) EpochKey(epoch uint64) *EpochKey {
+	h := EpochBase(epoch)
+	h.ScalarMult(sk.A0, h)
+	return &EpochKey{
+		Epoch: epoch,
+		H:     h,
+	}
+}
+
+type TempReEncryptionKey struct {
+	Epoch uint64
+	RK    *bls.G2
+}
+
+func TempReEncryptionKeyGen(_ *PublicParams, aliceSK *TempPrivateKey, bobEK *EpochKey) *TempReEncryptionKey {
+	rk := new(bls.G2)
+	rk.ScalarMult(aliceSK.AR, bobEK.H)
+
+	return &TempReEncryptionKey{
+		Epoch: bobEK.Epoch,
+		RK:    rk,
+	}
+}
+
+// TempCiphertext1 is a re-encryptable ciphertext for a given epoch.
+type TempCiphertext1 struct {
+	Epoch uint64
+	Alpha *bls.Gt
+	Beta  *bls.G1
+}
+
+func TempEncrypt(pp *PublicParams, pk *TempPublicKey, epoch uint64, msg *bls.Gt) *TempCiphertext1 {
+	k := blspairing.NewRandomScalar()
+
+	alpha := bls.Pair(pk.G1ToAR, EpochBase(epoch))
+	alpha.Exp(alpha, k)
+	alpha.Mul(alpha, msg)
+
+	beta := new(bls.G1)
+	beta.ScalarMult(k, pp.G1)
+
+	return &TempCiphertext1{
+		Epoch: epoch,
+		Alpha: alpha,
+		Beta:  beta,
+	}
+}
+
+func TempDecrypt1(pp *PublicParams, sk *TempPrivateKey, ct1 *TempCiphertext1) *bls.Gt {
+	tmp := bls.Pair(ct1.Beta, EpochBase(ct1.Epoch))
+	tmp.Exp(tmp, sk.AR)
+	tmp.Inv(tmp)
+
+	msg := new(bls.Gt)
+	msg.Mul(ct1.Alpha, tmp)
+
+	return msg
+}
+
+// TempCiphertext2 is the result of re-encrypting a [TempCiphertext1].
+type TempCiphertext2 struct {
+	Epoch uint64
+	Alpha *bls.Gt
+	Beta  *bls.Gt
+}
+
+// TempReEncrypt returns [ErrEpochMismatch] if the ciphertext's epoch differs
+// from the key's.  Note that the check is a convenience: relabeling a
+// ciphertext's epoch does not let an expired key re-encrypt it correctly.
+func TempReEncrypt(pp *PublicParams, rk *TempReEncryptionKey, ct1 *TempCiphertext1) (*TempCiphertext2, error) {
+	if ct1.Epoch != rk.Epoch {
+		return nil, ErrEpochMismatch
+	}
+
+	return &TempCiphertext2{
+		Epoch: ct1.Epoch,
+		Alpha: blspairing.CloneGt(ct1.Alpha),
+		Beta:  bls.Pair(ct1.Beta, rk.RK),
+	}, nil
+}
+
+// TempEncrypt2 encrypts msg to the holder of pk so that the ciphertext
+// cannot be re-encrypted.  The ciphertext does not depend on an epoch: its
+// Epoch is 0, and [TempDecrypt2] ignores it.
+func TempEncrypt2(pp *PublicParams, pk *TempPublicKey, msg *bls.Gt) *TempCiphertext2 {
+	k := blspairing.NewRandomScalar()
+
+	alpha := new(bls.Gt)
+	alpha.Exp(pp.Z, k)
+	alpha.Mul(alpha, msg)
+
+	beta := new(bls.Gt)
+	beta.Exp(pk.ZToA0, k)
+
+	return &TempCiphertext2{
+		Alpha: alpha,
+		Beta:  beta,
+	}
+}
+
+func TempDecrypt2(pp *PublicParams, sk *TempPrivateKey, ct2 *TempCiphertext2) *bls.Gt {
+	a0Inv := new(bls.Scalar)
+	a0Inv.Inv(sk.A0)
+	tmp := new(bls.Gt)
+	tmp.Exp(ct2.Beta, a0Inv)
+	tmp.Inv(tmp)
+
+	msg := new(bls.Gt)
+	msg.Mul(ct2.Alpha, tmp)
+
+	return msg
+}
@@ -0,0 +1,100 @@
+package afgh05
+
+import (
+	"testing"
+
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestTempEncryptDecrypt1(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := TempKeyGen(pp)
+
+	msg := blspairing.NewRandomGt()
+	ct1 := TempEncrypt(pp, alicePK, 7, msg)
+	got := TempDecrypt1(pp, aliceSK, ct1)
+
+	if !got.IsEqual(msg) {
+		t.Fatal("TempDecrypt1 did not produce the original message")
+	}
+}
+
+func TestTempEncryptReEncryptDecrypt2(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := TempKeyGen(pp)
+	_, bobSK := TempKeyGen(pp)
+
+	const epoch = 7
+	rk := TempReEncryptionKeyGen(pp, aliceSK, bobSK.EpochKey(epoch))
+
+	msg := blspairing.NewRandomGt()
+	ct1 := TempEncrypt(pp, alicePK, epoch, msg)
+	ct2, err := TempReEncrypt(pp, rk, ct1)
+	if err != nil {
+		t.Fatalf("TempReEncrypt failed: %v", err)
+	}
+	got := TempDecrypt2(pp, bobSK, ct2)
+
+	if !got.IsEqual(msg) {
+		t.Fatal("TempDecrypt2 did not produce the original message")
+	}
+}
+
+func TestTempEncryptDecrypt2(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := TempKeyGen(pp)
+	_, bobSK := TempKeyGen(pp)
+
+	msg := blspairing.NewRandomGt()
+	ct2 := TempEncrypt2(pp, alicePK, msg)
+
+	if !TempDecrypt2(pp, aliceSK, ct2).IsEqual(msg) {
+		t.Fatal("TempDecrypt2 did not produce the original message")
+	}
+	if TempDecrypt2(pp, bobSK, ct2).IsEqual(msg) {
+		t.Fatal("TempDecrypt2 decrypted with another user's key")
+	}
+}
+
+func TestTempReEncrypt_expiredKey(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := TempKeyGen(pp)
+	_, bobSK := TempKeyGen(pp)
+
+	rk := TempReEncryptionKeyGen(pp, aliceSK, bobSK.EpochKey(7))
+
+	msg := blspairing.NewRandomGt()
+	ct1 := TempEncrypt(pp, alicePK, 8, msg)
+	if _, err := TempReEncrypt(pp, rk, ct1); err != ErrEpochMismatch {
+		t.Fatalf("expected ErrEpochMismatch, but got %v", err)
+	}
+
+	// Relabeling the ciphertext's epoch gets it past the check, but the
+	// expired key still does not yield the message.
+	ct1.Epoch = 7
+	ct2, err := TempReEncrypt(pp, rk, ct1)
+	if err != nil {
+		t.Fatalf("TempReEncrypt failed: %v", err)
+	}
+	got := TempDecrypt2(pp, bobSK, ct2)
+	if got.IsEqual(msg) {
+		t.Fatal("an expired re-encryption key produced a decryptable ciphertext")
+	}
+}
+
+func BenchmarkTempReEncrypt(b *testing.B) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := TempKeyGen(pp)
+	_, bobSK := TempKeyGen(pp)
+	rk := TempReEncryptionKeyGen(pp, aliceSK, bobSK.EpochKey(1))
+
+	msg := blspairing.NewRandomGt()
+	ct1 := TempEncrypt(pp, alicePK, 1, msg)
+
+	for b.Loop() {
+		_, err := TempReEncrypt(pp, rk, ct1)
+		if err != nil {
+			b.Fatalf("TempReEncrypt failed: %v", err)
+		}
+	}
+}
@@ -138,3 +138,37 @@ func Decrypt(pp *PublicParams, sk *PrivateKey, ct *Ciphertext) *ecc.Point {
 
 	return msg
 }
+
+// EncryptBytes encodes msg as one or more curve points (see
+// [ecc.EncodeMessage]) and encrypts each point.  Each chunk is encrypted
+// independently, so an adversary can drop or reorder chunks.
+func EncryptBytes(pp *PublicParams, pk *PublicKey, msg []byte) ([]*Ciphertext, error) {
+	pts, err := ecc.EncodeMessage(pp.Curve, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	cts := make([]*Ciphertext, len(pts))
+	for i, pt := range pts {
+		cts[i], err = Encrypt(pp, pk, pt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cts, nil
+}
+
+func ReEncryptBytes(pp *PublicParams, rk *ReEncryptionKey, cts []*Ciphertext) {
+	for _, ct := range cts {
+		ReEncrypt(pp, rk, ct)
+	}
+}
+
+func DecryptBytes(pp *PublicParams, sk *PrivateKey, cts []*Ciphertext) ([]byte, error) {
+	pts := make([]*ecc.Point, len(cts))
+	for i, ct := range cts {
+		pts[i] = Decrypt(pp, sk, ct)
+	}
+	return ecc.DecodeMessage(pp.Curve, pts)
+}
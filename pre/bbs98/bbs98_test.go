@@ -1,6 +1,7 @@
 package bbs98
 
 import (
+	"bytes"
 	"crypto/elliptic"
 	"testing"
 
@@ -73,6 +74,52 @@ func TestEncryptReEncryptDecrypt(t *testing.T) {
 	}
 }
 
+func TestEncryptBytesReEncryptBytesDecryptBytes(t *testing.T) {
+	trials := []struct {
+		name  string
+		curve elliptic.Curve
+	}{
+		{"P-224", elliptic.P224()},
+		{"P-256", elliptic.P256()},
+		{"P-384", elliptic.P384()},
+		{"P-384/circl", circlp384.P384()},
+		{"P-521", elliptic.P521()},
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.  The quick brown fox jumps over the lazy dog.")
+
+	for _, trial := range trials {
+		t.Run(trial.name, func(t *testing.T) {
+			pp := NewPublicParams(trial.curve)
+
+			alicePK, aliceSK := KeyGen(pp)
+			_, bobSK := KeyGen(pp)
+
+			cts, err := EncryptBytes(pp, alicePK, msg)
+			if err != nil {
+				t.Fatalf("EncryptBytes failed: %v", err)
+			}
+			got, err := DecryptBytes(pp, aliceSK, cts)
+			if err != nil {
+				t.Fatalf("DecryptBytes failed: %v", err)
+			}
+			if !bytes.Equal(msg, got) {
+				t.Fatalf("expected decrypted message to be %q, but got %q", msg, got)
+			}
+
+			rkAliceToBob := ReEncryptionKeyGen(pp, aliceSK, bobSK)
+			ReEncryptBytes(pp, rkAliceToBob, cts)
+			got, err = DecryptBytes(pp, bobSK, cts)
+			if err != nil {
+				t.Fatalf("DecryptBytes failed: %v", err)
+			}
+			if !bytes.Equal(msg, got) {
+				t.Fatalf("expected re-encrypted message to be %q, but got %q", msg, got)
+			}
+		})
+	}
+}
+
 func BenchmarkKeyGen(b *testing.B) {
 	trials := []struct {
 		name  string
@@ -0,0 +1,165 @@
+package bbs98
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/etclab/ncircl/ecc"
+	"github.com/etclab/ncircl/util/binaryx"
+)
+
+// Encodings start with a version byte and a byte for the kind of value
+// encoded (see [binaryx]), followed by a byte that identifies the curve.
+// Points are compressed, and scalars are padded to the byte length of the
+// curve's order.
+//
+// A point does not record its curve, so, unlike the other pre packages,
+// encoding and decoding take the [PublicParams].  Decoding fails if the
+// encoding is for another curve.
+const encodingVersion = 1
+
+const (
+	kindPublicKey byte = iota + 1
+	kindPrivateKey
+	kindReEncryptionKey
+	kindCiphertext
+)
+
+var (
+	ErrInvalidEncoding  = errors.New("bbs98: invalid encoding")
+	ErrUnsupportedCurve = errors.New("bbs98: unsupported curve")
+)
+
+var curveIDs = map[string]byte{
+	"P-224": 1,
+	"P-256": 2,
+	"P-384": 3,
+	"P-521": 4,
+}
+
+func newEncoder(pp *PublicParams, kind byte) (*binaryx.Encoder, error) {
+	id, ok := curveIDs[pp.Curve.Params().Name]
+	if !ok {
+		return nil, ErrUnsupportedCurve
+	}
+	e := binaryx.NewEncoder(encodingVersion, kind)
+	e.PutByte(id)
+	return e, nil
+}
+
+func newDecoder(pp *PublicParams, data []byte, kind byte) (*binaryx.Decoder, error) {
+	id, ok := curveIDs[pp.Curve.Params().Name]
+	if !ok {
+		return nil, ErrUnsupportedCurve
+	}
+	d := binaryx.NewDecoder(data, encodingVersion, kind)
+	if d.Byte() != id {
+		if err := d.Finish(); err != nil {
+			return nil, decodeError(err)
+		}
+		return nil, decodeError(binaryx.ErrInvalidHeader)
+	}
+	return d, nil
+}
+
+func decodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+}
+
+func scalarSize(pp *PublicParams) int {
+	return (pp.Curve.Params().N.BitLen() + 7) / 8
+}
+
+func MarshalPublicKey(pp *PublicParams, pk *PublicKey) ([]byte, error) {
+	e, err := newEncoder(pp, kindPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	e.PutPoint(pp.Curve, pk.X, pk.Y)
+	return e.Bytes(), nil
+}
+
+func UnmarshalPublicKey(pp *PublicParams, data []byte) (*PublicKey, error) {
+	d, err := newDecoder(pp, data, kindPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	pk := new(PublicKey)
+	pk.X, pk.Y = d.Point(pp.Curve)
+	if err := d.Finish(); err != nil {
+		return nil, decodeError(err)
+	}
+	return pk, nil
+}
+
+func MarshalPrivateKey(pp *PublicParams, sk *PrivateKey) ([]byte, error) {
+	e, err := newEncoder(pp, kindPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	e.PutBigInt(sk.K, scalarSize(pp))
+	return e.Bytes(), nil
+}
+
+func UnmarshalPrivateKey(pp *PublicParams, data []byte) (*PrivateKey, error) {
+	d, err := newDecoder(pp, data, kindPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	sk := &PrivateKey{K: d.BigInt(scalarSize(pp), pp.Curve.Params().N)}
+	if err := d.Finish(); err != nil {
+		return nil, decodeError(err)
+	}
+	return sk, nil
+}
+
+// MarshalReEncryptionKey reduces the key modulo the curve's order, which
+// does not change its effect.
+func MarshalReEncryptionKey(pp *PublicParams, rk *ReEncryptionKey) ([]byte, error) {
+	e, err := newEncoder(pp, kindReEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	e.PutBigInt(new(big.Int).Mod(rk.RK, pp.Curve.Params().N), scalarSize(pp))
+	return e.Bytes(), nil
+}
+
+func UnmarshalReEncryptionKey(pp *PublicParams, data []byte) (*ReEncryptionKey, error) {
+	d, err := newDecoder(pp, data, kindReEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	rk := &ReEncryptionKey{RK: d.BigInt(scalarSize(pp), pp.Curve.Params().N)}
+	if err := d.Finish(); err != nil {
+		return nil, decodeError(err)
+	}
+	return rk, nil
+}
+
+func MarshalCiphertext(pp *PublicParams, ct *Ciphertext) ([]byte, error) {
+	e, err := newEncoder(pp, kindCiphertext)
+	if err != nil {
+		return nil, err
+	}
+	e.PutPoint(pp.Curve, ct.C1.X, ct.C1.Y)
+	e.PutPoint(pp.Curve, ct.C2.X, ct.C2.Y)
+	return e.Bytes(), nil
+}
+
+func UnmarshalCiphertext(pp *PublicParams, data []byte) (*Ciphertext, error) {
+	d, err := newDecoder(pp, data, kindCiphertext)
+	if err != nil {
+		return nil, err
+	}
+	ct := &Ciphertext{C1: new(ecc.Point), C2: new(ecc.Point)}
+	ct.C1.X, ct.C1.Y = d.Point(pp.Curve)
+	ct.C2.X, ct.C2.Y = d.Point(pp.Curve)
+	if err := d.Finish(); err != nil {
+		return nil, decodeError(err)
+	}
+	return ct, nil
+}
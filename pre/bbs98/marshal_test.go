@@ -0,0 +1,99 @@
+package bbs98
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"testing"
+
+	"github.com/etclab/ncircl/ecc"
+	"github.com/etclab/ncircl/util/binaryx"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	pp := NewPublicParams(elliptic.P256())
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+	rk := ReEncryptionKeyGen(pp, aliceSK, bobSK)
+
+	data, err := MarshalPublicKey(pp, bobPK)
+	if err != nil {
+		t.Fatalf("MarshalPublicKey failed: %v", err)
+	}
+	gotPK, err := UnmarshalPublicKey(pp, data)
+	if err != nil {
+		t.Fatalf("UnmarshalPublicKey failed: %v", err)
+	}
+	if !gotPK.Point.Equal(&bobPK.Point) {
+		t.Fatal("PublicKey did not round-trip")
+	}
+
+	data, err = MarshalPrivateKey(pp, bobSK)
+	if err != nil {
+		t.Fatalf("MarshalPrivateKey failed: %v", err)
+	}
+	gotSK, err := UnmarshalPrivateKey(pp, data)
+	if err != nil {
+		t.Fatalf("UnmarshalPrivateKey failed: %v", err)
+	}
+
+	data, err = MarshalReEncryptionKey(pp, rk)
+	if err != nil {
+		t.Fatalf("MarshalReEncryptionKey failed: %v", err)
+	}
+	gotRK, err := UnmarshalReEncryptionKey(pp, data)
+	if err != nil {
+		t.Fatalf("UnmarshalReEncryptionKey failed: %v", err)
+	}
+
+	msg := ecc.NewRandomPoint(pp.Curve)
+	ct, err := Encrypt(pp, alicePK, msg)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	data, err = MarshalCiphertext(pp, ct)
+	if err != nil {
+		t.Fatalf("MarshalCiphertext failed: %v", err)
+	}
+	gotCT, err := UnmarshalCiphertext(pp, data)
+	if err != nil {
+		t.Fatalf("UnmarshalCiphertext failed: %v", err)
+	}
+
+	ReEncrypt(pp, gotRK, gotCT)
+	if !Decrypt(pp, gotSK, gotCT).Equal(msg) {
+		t.Fatal("keys and ciphertext did not round-trip")
+	}
+}
+
+func TestUnmarshal_strict(t *testing.T) {
+	pp := NewPublicParams(elliptic.P256())
+	alicePK, _ := KeyGen(pp)
+	ct, err := Encrypt(pp, alicePK, ecc.NewRandomPoint(pp.Curve))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	data, err := MarshalCiphertext(pp, ct)
+	if err != nil {
+		t.Fatalf("MarshalCiphertext failed: %v", err)
+	}
+
+	if _, err := UnmarshalCiphertext(pp, data[:len(data)-1]); !errors.Is(err, binaryx.ErrTruncated) {
+		t.Fatalf("expected ErrTruncated, but got %v", err)
+	}
+	if _, err := UnmarshalCiphertext(pp, append(data, 0)); !errors.Is(err, binaryx.ErrTrailingData) {
+		t.Fatalf("expected ErrTrailingData, but got %v", err)
+	}
+	if _, err := UnmarshalPublicKey(pp, data); !errors.Is(err, binaryx.ErrInvalidHeader) {
+		t.Fatalf("expected ErrInvalidHeader, but got %v", err)
+	}
+	if _, err := UnmarshalCiphertext(NewPublicParams(elliptic.P384()), data); !errors.Is(err, ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for another curve, but got %v", err)
+	}
+
+	bad := make([]byte, len(data))
+	copy(bad, data)
+	bad[binaryx.HeaderSize+1] ^= 0xff // corrupt the compression byte of C1
+	if _, err := UnmarshalCiphertext(pp, bad); !errors.Is(err, binaryx.ErrInvalidElement) {
+		t.Fatalf("expected ErrInvalidElement, but got %v", err)
+	}
+}
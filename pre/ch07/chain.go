@@ -0,0 +1,71 @@
+package ch07
+
+import (
+	"fmt"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// Reverse returns the re-encryption key for the opposite direction: if rk
+// re-encrypts from Alice to Bob, the returned key re-encrypts from Bob to
+// Alice.
+func (rk *ReEncryptionKey) Reverse() *ReEncryptionKey {
+	rev := new(bls.Scalar)
+	rev.Inv(rk.RK)
+	return &ReEncryptionKey{
+		RK: rev,
+	}
+}
+
+func (rk *ReEncryptionKey) Clone() *ReEncryptionKey {
+	return &ReEncryptionKey{
+		RK: blspairing.CloneScalar(rk.RK),
+	}
+}
+
+// Hop is one step of a re-encryption chain: RK re-encrypts ciphertexts for
+// From into ciphertexts for To.
+type Hop struct {
+	From *PublicKey
+	To   *PublicKey
+	RK   *ReEncryptionKey
+}
+
+// HopRecord is the audit trail entry for one hop of a chain.  Ciphertext is
+// a copy of the ciphertext as it was after the hop.
+type HopRecord struct {
+	Index      int
+	From       *PublicKey
+	To         *PublicKey
+	Ciphertext *Ciphertext
+}
+
+// ReEncryptChain re-encrypts ct along each hop in turn, for instance from
+// Alice to Bob to Carol.  Unlike [ReEncrypt], ct is not modified.  Before each
+// hop, the ciphertext is checked against the hop's From key, and after, against
+// its To key.  On success, the function returns the final ciphertext and one
+// [HopRecord] per hop.  On failure, it returns the records for the hops that
+// succeeded, and an error that wraps [ErrInvalidSignature] or
+// [ErrInvalidCiphertext] and names the failing hop.
+func ReEncryptChain(pp *PublicParams, ct *Ciphertext, hops []*Hop) (*Ciphertext, []*HopRecord, error) {
+	cur := ct.Clone()
+	records := make([]*HopRecord, 0, len(hops))
+
+	for i, hop := range hops {
+		if err := cur.Check(pp, hop.From); err != nil {
+			return nil, records, fmt.Errorf("ch07: hop %d: before re-encryption: %w", i, err)
+		}
+		if err := ReEncrypt(pp, hop.RK, hop.To, cur); err != nil {
+			return nil, records, fmt.Errorf("ch07: hop %d: after re-encryption: %w", i, err)
+		}
+		records = append(records, &HopRecord{
+			Index:      i,
+			From:       hop.From,
+			To:         hop.To,
+			Ciphertext: cur.Clone(),
+		})
+	}
+
+	return cur, records, nil
+}
@@ -0,0 +1,109 @@
+package ch07
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestReverse(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+	rkBobToAlice := ReEncryptionKeyGen(pp, aliceSK, bobSK).Reverse()
+
+	msg := blspairing.NewRandomGt()
+	ct := Encrypt(pp, bobPK, msg)
+	if err := ReEncrypt(pp, rkBobToAlice, alicePK, ct); err != nil {
+		t.Fatalf("ReEncrypt failed: %v", err)
+	}
+
+	got, err := Decrypt(pp, aliceSK, ct)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if !msg.IsEqual(got) {
+		t.Fatal("result of decryption does not equal original message")
+	}
+}
+
+func TestReEncryptChain(t *testing.T) {
+	pp := NewPublicParams()
+
+	const n = 5
+	pks := make([]*PublicKey, n)
+	sks := make([]*PrivateKey, n)
+	for i := range pks {
+		pks[i], sks[i] = KeyGen(pp)
+	}
+
+	// alternate between forward keys and reversed keys
+	hops := make([]*Hop, n-1)
+	for i := range hops {
+		var rk *ReEncryptionKey
+		if i%2 == 0 {
+			rk = ReEncryptionKeyGen(pp, sks[i], sks[i+1])
+		} else {
+			rk = ReEncryptionKeyGen(pp, sks[i+1], sks[i]).Reverse()
+		}
+		hops[i] = &Hop{From: pks[i], To: pks[i+1], RK: rk}
+	}
+
+	msg := blspairing.NewRandomGt()
+	ct := Encrypt(pp, pks[0], msg)
+	orig := ct.Clone()
+
+	got, records, err := ReEncryptChain(pp, ct, hops)
+	if err != nil {
+		t.Fatalf("ReEncryptChain failed: %v", err)
+	}
+	if len(records) != len(hops) {
+		t.Fatalf("expected %d hop records, but got %d", len(hops), len(records))
+	}
+	if !ct.B.IsEqual(orig.B) {
+		t.Fatal("ReEncryptChain modified its input ciphertext")
+	}
+
+	for i, rec := range records {
+		m, err := Decrypt(pp, sks[i+1], rec.Ciphertext)
+		if err != nil {
+			t.Fatalf("Decrypt of hop %d failed: %v", i, err)
+		}
+		if !msg.IsEqual(m) {
+			t.Fatalf("hop %d does not decrypt to the original message", i)
+		}
+	}
+
+	m, err := Decrypt(pp, sks[n-1], got)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if !msg.IsEqual(m) {
+		t.Fatal("result of decryption does not equal original message")
+	}
+}
+
+func TestReEncryptChain_brokenChain(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+	carolPK, carolSK := KeyGen(pp)
+	davePK, _ := KeyGen(pp)
+
+	hops := []*Hop{
+		{From: alicePK, To: bobPK, RK: ReEncryptionKeyGen(pp, aliceSK, bobSK)},
+		// the key is for Carol to Bob, not Bob to Dave
+		{From: bobPK, To: davePK, RK: ReEncryptionKeyGen(pp, carolSK, bobSK)},
+		{From: davePK, To: carolPK, RK: ReEncryptionKeyGen(pp, carolSK, bobSK)},
+	}
+
+	ct := Encrypt(pp, alicePK, blspairing.NewRandomGt())
+	_, records, err := ReEncryptChain(pp, ct, hops)
+	if !errors.Is(err, ErrInvalidCiphertext) {
+		t.Fatalf("expected ErrInvalidCiphertext, but got %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 hop record, but got %d", len(records))
+	}
+}
@@ -12,11 +12,11 @@
 // Model from Section 3.2 of that paper.
 //
 // Properties:
-// - CCA-secure
-// - bidirectional (a re-encryption key from Alice to Bob also permits
-// re-encryptino from Bob to Alice)
+//   - CCA-secure
+//   - bidirectional (a re-encryption key from Alice to Bob also permits
+//     re-encryption from Bob to Alice; see [ReEncryptionKey.Reverse])
 //   - multihop (a ciphertext may be re-encrypted multiple times---from Alice to Bob
-//     to Carol, etc.)
+//     to Carol, etc.; see [ReEncryptChain])
 //
 // [paper]: https://eprint.iacr.org/2007/171.pdf
 package ch07
@@ -0,0 +1,71 @@
+package ch07
+
+import (
+	"errors"
+
+	"github.com/etclab/ncircl/util/aesx"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+var (
+	ErrInvalidPayload = errors.New("ch07: invalid payload")
+)
+
+// HybridCiphertext is a ciphertext for an arbitrary byte payload.  The Header
+// encapsulates a random Gt element from which the AES-256-GCM key for the
+// Payload is derived.
+type HybridCiphertext struct {
+	Header  *Ciphertext
+	Payload []byte
+}
+
+func (ct *HybridCiphertext) Clone() *HybridCiphertext {
+	payload := make([]byte, len(ct.Payload))
+	copy(payload, ct.Payload)
+	return &HybridCiphertext{
+		Header:  ct.Header.Clone(),
+		Payload: payload,
+	}
+}
+
+// The payload is bound to the header's verification key and signed
+// components, none of which change under re-encryption.
+func headerAD(ct *Ciphertext) []byte {
+	m := make([]byte, 0, 1024)
+	m = append(m, ct.A...)
+	m = append(m, ct.MessageToSign()...)
+	return m
+}
+
+func EncryptBytes(pp *PublicParams, pk *PublicKey, msg []byte) (*HybridCiphertext, error) {
+	gt := blspairing.NewRandomGt()
+	header := Encrypt(pp, pk, gt)
+
+	payload, err := aesx.EncryptGCM(blspairing.KdfGtToAes256(gt), msg, headerAD(header))
+	if err != nil {
+		return nil, err
+	}
+
+	return &HybridCiphertext{
+		Header:  header,
+		Payload: payload,
+	}, nil
+}
+
+// ReEncryptBytes re-encrypts the header in place; the payload is untouched.
+func ReEncryptBytes(pp *PublicParams, rk *ReEncryptionKey, bobPK *PublicKey, ct *HybridCiphertext) error {
+	return ReEncrypt(pp, rk, bobPK, ct.Header)
+}
+
+func DecryptBytes(pp *PublicParams, sk *PrivateKey, ct *HybridCiphertext) ([]byte, error) {
+	gt, err := Decrypt(pp, sk, ct.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := aesx.DecryptGCM(blspairing.KdfGtToAes256(gt), ct.Payload, headerAD(ct.Header))
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	return msg, nil
+}
@@ -0,0 +1,65 @@
+package ch07
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptBytesDecryptBytes(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+
+	for _, n := range []int{0, 1, 32, 1000} {
+		msg := bytes.Repeat([]byte{'a'}, n)
+		ct, err := EncryptBytes(pp, alicePK, msg)
+		if err != nil {
+			t.Fatalf("EncryptBytes failed: %v", err)
+		}
+		got, err := DecryptBytes(pp, aliceSK, ct)
+		if err != nil {
+			t.Fatalf("DecryptBytes failed: %v", err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Fatalf("DecryptBytes did not produce the original %d-byte message", n)
+		}
+	}
+}
+
+func TestEncryptBytesReEncryptBytesDecryptBytes(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+	rkAliceToBob := ReEncryptionKeyGen(pp, aliceSK, bobSK)
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	ct, err := EncryptBytes(pp, alicePK, msg)
+	if err != nil {
+		t.Fatalf("EncryptBytes failed: %v", err)
+	}
+	if err := ReEncryptBytes(pp, rkAliceToBob, bobPK, ct); err != nil {
+		t.Fatalf("ReEncryptBytes failed: %v", err)
+	}
+	got, err := DecryptBytes(pp, bobSK, ct)
+	if err != nil {
+		t.Fatalf("DecryptBytes failed: %v", err)
+	}
+	if !bytes.Equal(got, msg) {
+		t.Fatal("DecryptBytes did not produce the original message")
+	}
+}
+
+func TestDecryptBytes_tampered(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	ct, err := EncryptBytes(pp, alicePK, msg)
+	if err != nil {
+		t.Fatalf("EncryptBytes failed: %v", err)
+	}
+
+	ct.Payload[len(ct.Payload)-1] ^= 1
+	if _, err := DecryptBytes(pp, aliceSK, ct); err != ErrInvalidPayload {
+		t.Fatalf("expected ErrInvalidPayload, but got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package ch07
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/etclab/ncircl/util/binaryx"
+)
+
+// Encodings start with a version byte and a byte for the kind of value
+// encoded; see [binaryx].  Group elements are compressed.
+const encodingVersion = 1
+
+const (
+	kindPublicKey byte = iota + 1
+	kindPrivateKey
+	kindReEncryptionKey
+	kindCiphertext
+)
+
+var ErrInvalidEncoding = errors.New("ch07: invalid encoding")
+
+func decodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+}
+
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindPublicKey)
+	e.PutG1(pk.Y)
+	return e.Bytes(), nil
+}
+
+func (pk *PublicKey) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindPublicKey)
+	y := d.G1()
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	pk.Y = y
+	return nil
+}
+
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindPrivateKey)
+	e.PutScalar(sk.X)
+	return e.Bytes(), nil
+}
+
+func (sk *PrivateKey) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindPrivateKey)
+	x := d.NonZeroScalar()
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	sk.X = x
+	return nil
+}
+
+func (rk *ReEncryptionKey) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindReEncryptionKey)
+	e.PutScalar(rk.RK)
+	return e.Bytes(), nil
+}
+
+func (rk *ReEncryptionKey) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindReEncryptionKey)
+	x := d.NonZeroScalar()
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	rk.RK = x
+	return nil
+}
+
+func (ct *Ciphertext) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindCiphertext)
+	e.PutBytes(ct.A)
+	e.PutG1(ct.B)
+	e.PutGt(ct.C)
+	e.PutG2(ct.D)
+	e.PutG2(ct.E)
+	e.PutBytes(ct.S)
+	return e.Bytes(), nil
+}
+
+func (ct *Ciphertext) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindCiphertext)
+	a := d.Bytes(ed25519.PublicKeySize)
+	b := d.G1()
+	c := d.Gt()
+	dd := d.G2()
+	e := d.G2()
+	s := d.Bytes(ed25519.SignatureSize)
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	ct.A, ct.B, ct.C, ct.D, ct.E, ct.S = a, b, c, dd, e, s
+	return nil
+}
@@ -0,0 +1,86 @@
+package ch07
+
+import (
+	"encoding"
+	"errors"
+	"testing"
+
+	"github.com/etclab/ncircl/util/binaryx"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+	rk := ReEncryptionKeyGen(pp, aliceSK, bobSK)
+
+	msg := blspairing.NewRandomGt()
+	ct := Encrypt(pp, alicePK, msg)
+
+	roundTrip := func(t *testing.T, in encoding.BinaryMarshaler, out encoding.BinaryUnmarshaler) {
+		data, err := in.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary failed: %v", err)
+		}
+		if err := out.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+	}
+
+	gotPK := new(PublicKey)
+	roundTrip(t, bobPK, gotPK)
+	if !gotPK.Y.IsEqual(bobPK.Y) {
+		t.Fatal("PublicKey did not round-trip")
+	}
+
+	gotSK := new(PrivateKey)
+	roundTrip(t, bobSK, gotSK)
+	if gotSK.X.IsEqual(bobSK.X) != 1 {
+		t.Fatal("PrivateKey did not round-trip")
+	}
+
+	gotRK := new(ReEncryptionKey)
+	roundTrip(t, rk, gotRK)
+	if gotRK.RK.IsEqual(rk.RK) != 1 {
+		t.Fatal("ReEncryptionKey did not round-trip")
+	}
+
+	gotCT := new(Ciphertext)
+	roundTrip(t, ct, gotCT)
+	if err := ReEncrypt(pp, gotRK, gotPK, gotCT); err != nil {
+		t.Fatalf("ReEncrypt failed: %v", err)
+	}
+	got, err := Decrypt(pp, gotSK, gotCT)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if !got.IsEqual(msg) {
+		t.Fatal("Ciphertext did not round-trip")
+	}
+}
+
+func TestUnmarshal_strict(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, _ := KeyGen(pp)
+	data, err := Encrypt(pp, alicePK, blspairing.NewRandomGt()).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	if err := new(Ciphertext).UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, binaryx.ErrTruncated) {
+		t.Fatalf("expected ErrTruncated, but got %v", err)
+	}
+	if err := new(Ciphertext).UnmarshalBinary(append(data, 0)); !errors.Is(err, binaryx.ErrTrailingData) {
+		t.Fatalf("expected ErrTrailingData, but got %v", err)
+	}
+	if err := new(PublicKey).UnmarshalBinary(data); !errors.Is(err, binaryx.ErrInvalidHeader) {
+		t.Fatalf("expected ErrInvalidHeader, but got %v", err)
+	}
+
+	// B immediately follows the 32-byte signature verification key
+	data[binaryx.HeaderSize+32+10] ^= 1
+	if err := new(Ciphertext).UnmarshalBinary(data); !errors.Is(err, ErrInvalidEncoding) || !errors.Is(err, binaryx.ErrInvalidElement) {
+		t.Fatalf("expected ErrInvalidElement, but got %v", err)
+	}
+}
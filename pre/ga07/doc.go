@@ -0,0 +1,33 @@
+// Package ga07 implements the identity-based proxy re-encryption scheme IBP1
+// from the [paper]:
+//
+//	@inproceedings{07-acns-identity_based_proxy_reencryption,
+//	    title = {Identity-Based Proxy Re-encryption},
+//	    author = {Green, Matthew and Ateniese, Giuseppe},
+//	    booktitle = {Applied Cryptography and Network Security (ACNS)},
+//	    year = {2007},
+//	}
+//
+// Section 4 of that paper describes the scheme.  The scheme reuses the
+// parameters and private keys of the Boneh-Franklin IBE in [bf01]: a holder
+// of a private key extracted by a [bf01.PrivateKeyGenerator] can issue a
+// re-encryption key from their identity to any other identity, without
+// interaction and without a per-user PKI.
+//
+// # Changes from Paper
+// The paper assumes a symmetric pairing.  On BLS12-381, identities hash to
+// G1 (as in bf01), and the ciphertext's randomness g^r lives in G2 alongside
+// the master public key.  The hash H2 from Gt to the group is realized by
+// hashing the encoding of the Gt element onto G1.
+//
+// # Properties
+//   - unidirectional
+//   - single-hop
+//   - non-interactive (the delegatee is not involved in generating the
+//     re-encryption key)
+//   - CPA-secure (aka, semantically secure)
+//   - not collusion-resistant: the proxy and the delegatee together can
+//     recover the delegator's private key, as the paper notes for IBP1
+//
+// [paper]: https://eprint.iacr.org/2006/473.pdf
+package ga07
@@ -0,0 +1,31 @@
+package ga07_test
+
+import (
+	"fmt"
+
+	"github.com/etclab/ncircl/ibe/bf01"
+	"github.com/etclab/ncircl/pre/ga07"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func Example() {
+	aliceID := []byte("alice@example.com")
+	bobID := []byte("bob@example.com")
+
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	aliceSK := pkg.Extract(aliceID)
+	bobSK := pkg.Extract(bobID)
+
+	// Alice delegates to Bob's identity; Bob need not be online.
+	rkAliceToBob := ga07.ReEncryptionKeyGen(pp, aliceSK, bobID)
+
+	msg := blspairing.NewRandomGt()
+
+	ct1 := ga07.Encrypt(pp, aliceID, msg)
+	ct2 := ga07.ReEncrypt(pp, rkAliceToBob, ct1)
+	got := ga07.Decrypt2(pp, bobSK, ct2)
+
+	fmt.Println(got.IsEqual(msg))
+	// Output:
+	// true
+}
@@ -0,0 +1,97 @@
+package ga07
+
+import (
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/ibe/bf01"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+var h2DomainSepTag = []byte("ga07-H2")
+
+// H2: G_T \rightarrow G_1
+func H2(x *bls.Gt) *bls.G1 {
+	return blspairing.HashBytesToG1(blspairing.GtToBytes(x), h2DomainSepTag)
+}
+
+type Ciphertext1 struct {
+	C1 *bls.G2
+	C2 *bls.Gt
+}
+
+func Encrypt(pp *bf01.PublicParams, id []byte, msg *bls.Gt) *Ciphertext1 {
+	r := blspairing.NewRandomScalar()
+
+	C1 := new(bls.G2)
+	C1.ScalarMult(r, bls.G2Generator())
+
+	C2 := bls.Pair(blspairing.HashBytesToG1(id, nil), pp.MPK)
+	C2.Exp(C2, r)
+	C2.Mul(C2, msg)
+
+	return &Ciphertext1{
+		C1: C1,
+		C2: C2,
+	}
+}
+
+func Decrypt1(_ *bf01.PublicParams, sk *bf01.PrivateKey, ct1 *Ciphertext1) *bls.Gt {
+	tmp := bls.Pair(sk.SK, ct1.C1)
+	tmp.Inv(tmp)
+
+	msg := new(bls.Gt)
+	msg.Mul(ct1.C2, tmp)
+
+	return msg
+}
+
+// ReEncryptionKey consists of R, an encryption of a random X to the
+// delegatee's identity, and RK = sk^{-1} * H2(X).
+type ReEncryptionKey struct {
+	R  *Ciphertext1
+	RK *bls.G1
+}
+
+func ReEncryptionKeyGen(pp *bf01.PublicParams, aliceSK *bf01.PrivateKey, bobID []byte) *ReEncryptionKey {
+	x := blspairing.NewRandomGt()
+
+	rk := blspairing.CloneG1(aliceSK.SK)
+	rk.Neg()
+	rk.Add(rk, H2(x))
+
+	return &ReEncryptionKey{
+		R:  Encrypt(pp, bobID, x),
+		RK: rk,
+	}
+}
+
+type Ciphertext2 struct {
+	C1 *bls.G2
+	C2 *bls.Gt
+	R  *Ciphertext1
+}
+
+func ReEncrypt(_ *bf01.PublicParams, rk *ReEncryptionKey, ct1 *Ciphertext1) *Ciphertext2 {
+	C2 := bls.Pair(rk.RK, ct1.C1)
+	C2.Mul(C2, ct1.C2)
+
+	return &Ciphertext2{
+		C1: blspairing.CloneG2(ct1.C1),
+		C2: C2,
+		R: &Ciphertext1{
+			C1: blspairing.CloneG2(rk.R.C1),
+			C2: blspairing.CloneGt(rk.R.C2),
+		},
+	}
+}
+
+func Decrypt2(pp *bf01.PublicParams, sk *bf01.PrivateKey, ct2 *Ciphertext2) *bls.Gt {
+	x := Decrypt1(pp, sk, ct2.R)
+
+	tmp := bls.Pair(H2(x), ct2.C1)
+	tmp.Inv(tmp)
+
+	msg := new(bls.Gt)
+	msg.Mul(ct2.C2, tmp)
+
+	return msg
+}
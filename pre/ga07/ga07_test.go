@@ -0,0 +1,92 @@
+package ga07
+
+import (
+	"testing"
+
+	"github.com/etclab/ncircl/ibe/bf01"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestEncryptDecrypt1(t *testing.T) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	aliceID := []byte("alice@example.com")
+	aliceSK := pkg.Extract(aliceID)
+
+	msg := blspairing.NewRandomGt()
+	ct1 := Encrypt(pp, aliceID, msg)
+	got := Decrypt1(pp, aliceSK, ct1)
+
+	if !got.IsEqual(msg) {
+		t.Fatal("Decrypt1 did not produce the original message")
+	}
+}
+
+func TestEncryptReEncryptDecrypt2(t *testing.T) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	aliceID := []byte("alice@example.com")
+	bobID := []byte("bob@example.com")
+	aliceSK := pkg.Extract(aliceID)
+	bobSK := pkg.Extract(bobID)
+
+	rkAliceToBob := ReEncryptionKeyGen(pp, aliceSK, bobID)
+
+	msg := blspairing.NewRandomGt()
+	ct1 := Encrypt(pp, aliceID, msg)
+	ct2 := ReEncrypt(pp, rkAliceToBob, ct1)
+	got := Decrypt2(pp, bobSK, ct2)
+
+	if !got.IsEqual(msg) {
+		t.Fatal("Decrypt2 did not produce the original message")
+	}
+}
+
+func TestDecrypt2_wrongIdentity(t *testing.T) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	aliceID := []byte("alice@example.com")
+	bobID := []byte("bob@example.com")
+	aliceSK := pkg.Extract(aliceID)
+	carolSK := pkg.Extract([]byte("carol@example.com"))
+
+	rkAliceToBob := ReEncryptionKeyGen(pp, aliceSK, bobID)
+
+	msg := blspairing.NewRandomGt()
+	ct2 := ReEncrypt(pp, rkAliceToBob, Encrypt(pp, aliceID, msg))
+	got := Decrypt2(pp, carolSK, ct2)
+
+	if got.IsEqual(msg) {
+		t.Fatal("Decrypt2 succeeded with a key for an identity other than the delegatee")
+	}
+}
+
+func BenchmarkReEncryptionKeyGen(b *testing.B) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	aliceSK := pkg.Extract([]byte("alice@example.com"))
+	bobID := []byte("bob@example.com")
+	for b.Loop() {
+		_ = ReEncryptionKeyGen(pp, aliceSK, bobID)
+	}
+}
+
+func BenchmarkReEncrypt(b *testing.B) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	aliceID := []byte("alice@example.com")
+	aliceSK := pkg.Extract(aliceID)
+	rkAliceToBob := ReEncryptionKeyGen(pp, aliceSK, []byte("bob@example.com"))
+	ct1 := Encrypt(pp, aliceID, blspairing.NewRandomGt())
+	for b.Loop() {
+		_ = ReEncrypt(pp, rkAliceToBob, ct1)
+	}
+}
+
+func BenchmarkDecrypt2(b *testing.B) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	aliceID := []byte("alice@example.com")
+	bobID := []byte("bob@example.com")
+	aliceSK := pkg.Extract(aliceID)
+	bobSK := pkg.Extract(bobID)
+	rkAliceToBob := ReEncryptionKeyGen(pp, aliceSK, bobID)
+	ct2 := ReEncrypt(pp, rkAliceToBob, Encrypt(pp, aliceID, blspairing.NewRandomGt()))
+	for b.Loop() {
+		_ = Decrypt2(pp, bobSK, ct2)
+	}
+}
@@ -0,0 +1,117 @@
+package lv08
+
+import (
+	"crypto/ed25519"
+	"errors"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/util/aesx"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+var (
+	ErrInvalidPayload = errors.New("lv08: invalid payload")
+)
+
+// HybridCiphertext1 is a first-level (non re-encryptable) ciphertext for an
+// arbitrary byte payload.  The Header encapsulates a random Gt element from
+// which the AES-256-GCM key for the Payload is derived.
+type HybridCiphertext1 struct {
+	Header  *Ciphertext1
+	Payload []byte
+}
+
+// HybridCiphertext2 is a second-level (re-encryptable) ciphertext for an
+// arbitrary byte payload.
+type HybridCiphertext2 struct {
+	Header  *Ciphertext2
+	Payload []byte
+}
+
+// The payload is bound to the header's verification key and signed
+// components, which are identical in a second-level ciphertext and its
+// re-encryption.
+func headerAD(svk ed25519.PublicKey, c3 *bls.Gt, c4 *bls.G1) []byte {
+	m := make([]byte, 0, 1024)
+	m = append(m, svk...)
+	m = append(m, blspairing.GtToBytes(c3)...)
+	m = append(m, c4.Bytes()...)
+	return m
+}
+
+func Encrypt1Bytes(pp *PublicParams, pk *PublicKey, msg []byte) (*HybridCiphertext1, error) {
+	gt := blspairing.NewRandomGt()
+	header := Encrypt1(pp, pk, gt)
+
+	ad := headerAD(header.C1, header.C3, header.C4)
+	payload, err := aesx.EncryptGCM(blspairing.KdfGtToAes256(gt), msg, ad)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HybridCiphertext1{
+		Header:  header,
+		Payload: payload,
+	}, nil
+}
+
+func Decrypt1Bytes(pp *PublicParams, sk *PrivateKey, ct *HybridCiphertext1) ([]byte, error) {
+	gt, err := Decrypt1(pp, sk, ct.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	ad := headerAD(ct.Header.C1, ct.Header.C3, ct.Header.C4)
+	msg, err := aesx.DecryptGCM(blspairing.KdfGtToAes256(gt), ct.Payload, ad)
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	return msg, nil
+}
+
+func Encrypt2Bytes(pp *PublicParams, pk *PublicKey, msg []byte) (*HybridCiphertext2, error) {
+	gt := blspairing.NewRandomGt()
+	header := Encrypt2(pp, pk, gt)
+
+	ad := headerAD(header.C1, header.C3, header.C4)
+	payload, err := aesx.EncryptGCM(blspairing.KdfGtToAes256(gt), msg, ad)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HybridCiphertext2{
+		Header:  header,
+		Payload: payload,
+	}, nil
+}
+
+func Decrypt2Bytes(pp *PublicParams, sk *PrivateKey, ct *HybridCiphertext2) ([]byte, error) {
+	gt, err := Decrypt2(pp, sk, ct.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	ad := headerAD(ct.Header.C1, ct.Header.C3, ct.Header.C4)
+	msg, err := aesx.DecryptGCM(blspairing.KdfGtToAes256(gt), ct.Payload, ad)
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	return msg, nil
+}
+
+// ReEncryptBytes re-encrypts only the header; the payload is carried over as
+// is.
+func ReEncryptBytes(pp *PublicParams, rk *ReEncryptionKey, ct2 *HybridCiphertext2) (*HybridCiphertext1, error) {
+	header, err := ReEncrypt(pp, rk, ct2.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := make([]byte, len(ct2.Payload))
+	copy(payload, ct2.Payload)
+
+	return &HybridCiphertext1{
+		Header:  header,
+		Payload: payload,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package lv08
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncrypt1BytesDecrypt1Bytes(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+
+	for _, n := range []int{0, 1, 32, 1000} {
+		msg := bytes.Repeat([]byte{'a'}, n)
+		ct1, err := Encrypt1Bytes(pp, alicePK, msg)
+		if err != nil {
+			t.Fatalf("Encrypt1Bytes failed: %v", err)
+		}
+		got, err := Decrypt1Bytes(pp, aliceSK, ct1)
+		if err != nil {
+			t.Fatalf("Decrypt1Bytes failed: %v", err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Fatalf("Decrypt1Bytes did not produce the original %d-byte message", n)
+		}
+	}
+}
+
+func TestEncrypt2BytesDecrypt2Bytes(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+
+	for _, n := range []int{0, 1, 32, 1000} {
+		msg := bytes.Repeat([]byte{'a'}, n)
+		ct2, err := Encrypt2Bytes(pp, alicePK, msg)
+		if err != nil {
+			t.Fatalf("Encrypt2Bytes failed: %v", err)
+		}
+		got, err := Decrypt2Bytes(pp, aliceSK, ct2)
+		if err != nil {
+			t.Fatalf("Decrypt2Bytes failed: %v", err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Fatalf("Decrypt2Bytes did not produce the original %d-byte message", n)
+		}
+	}
+}
+
+func TestReEncryptBytes(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+	rkAliceToBob := ReEncryptionKeyGen(pp, aliceSK, bobPK)
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	ct2, err := Encrypt2Bytes(pp, alicePK, msg)
+	if err != nil {
+		t.Fatalf("Encrypt2Bytes failed: %v", err)
+	}
+	ct1, err := ReEncryptBytes(pp, rkAliceToBob, ct2)
+	if err != nil {
+		t.Fatalf("ReEncryptBytes failed: %v", err)
+	}
+	got, err := Decrypt1Bytes(pp, bobSK, ct1)
+	if err != nil {
+		t.Fatalf("Decrypt1Bytes failed: %v", err)
+	}
+	if !bytes.Equal(got, msg) {
+		t.Fatal("Decrypt1Bytes did not produce the original message")
+	}
+}
+
+func TestDecryptBytes_tampered(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	ct2, err := Encrypt2Bytes(pp, alicePK, msg)
+	if err != nil {
+		t.Fatalf("Encrypt2Bytes failed: %v", err)
+	}
+
+	ct2.Payload[len(ct2.Payload)-1] ^= 1
+	if _, err := Decrypt2Bytes(pp, aliceSK, ct2); err != ErrInvalidPayload {
+		t.Fatalf("expected ErrInvalidPayload, but got %v", err)
+	}
+}
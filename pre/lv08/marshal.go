@@ -0,0 +1,139 @@
+package lv08
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/etclab/ncircl/util/binaryx"
+)
+
+// Encodings start with a version byte and a byte for the kind of value
+// encoded; see [binaryx].  Group elements are compressed.
+const encodingVersion = 1
+
+const (
+	kindPublicKey byte = iota + 1
+	kindPrivateKey
+	kindReEncryptionKey
+	kindCiphertext1
+	kindCiphertext2
+)
+
+var ErrInvalidEncoding = errors.New("lv08: invalid encoding")
+
+func decodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+}
+
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindPublicKey)
+	e.PutG1(pk.Y1)
+	e.PutG2(pk.Y2)
+	return e.Bytes(), nil
+}
+
+func (pk *PublicKey) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindPublicKey)
+	y1 := d.G1()
+	y2 := d.G2()
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	pk.Y1, pk.Y2 = y1, y2
+	return nil
+}
+
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindPrivateKey)
+	e.PutScalar(sk.X)
+	return e.Bytes(), nil
+}
+
+func (sk *PrivateKey) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindPrivateKey)
+	x := d.NonZeroScalar()
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	sk.X = x
+	return nil
+}
+
+// The encoding of a ReEncryptionKey includes the delegator's public key.
+func (rk *ReEncryptionKey) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindReEncryptionKey)
+	e.PutG1(rk.DelegatorPK.Y1)
+	e.PutG2(rk.DelegatorPK.Y2)
+	e.PutG1(rk.RK)
+	return e.Bytes(), nil
+}
+
+func (rk *ReEncryptionKey) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindReEncryptionKey)
+	y1 := d.G1()
+	y2 := d.G2()
+	g := d.G1()
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	rk.DelegatorPK = &PublicKey{Y1: y1, Y2: y2}
+	rk.RK = g
+	return nil
+}
+
+func (ct *Ciphertext1) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindCiphertext1)
+	e.PutBytes(ct.C1)
+	e.PutG2(ct.C2Prime)
+	e.PutG1(ct.C2DoublePrime)
+	e.PutG2(ct.C2TriplePrime)
+	e.PutGt(ct.C3)
+	e.PutG1(ct.C4)
+	e.PutBytes(ct.S)
+	return e.Bytes(), nil
+}
+
+func (ct *Ciphertext1) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindCiphertext1)
+	c1 := d.Bytes(ed25519.PublicKeySize)
+	c2Prime := d.G2()
+	c2DoublePrime := d.G1()
+	c2TriplePrime := d.G2()
+	c3 := d.Gt()
+	c4 := d.G1()
+	s := d.Bytes(ed25519.SignatureSize)
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	ct.C1, ct.C2Prime, ct.C2DoublePrime, ct.C2TriplePrime = c1, c2Prime, c2DoublePrime, c2TriplePrime
+	ct.C3, ct.C4, ct.S = c3, c4, s
+	return nil
+}
+
+func (ct *Ciphertext2) MarshalBinary() ([]byte, error) {
+	e := binaryx.NewEncoder(encodingVersion, kindCiphertext2)
+	e.PutBytes(ct.C1)
+	e.PutG2(ct.C2)
+	e.PutGt(ct.C3)
+	e.PutG1(ct.C4)
+	e.PutBytes(ct.S)
+	return e.Bytes(), nil
+}
+
+func (ct *Ciphertext2) UnmarshalBinary(data []byte) error {
+	d := binaryx.NewDecoder(data, encodingVersion, kindCiphertext2)
+	c1 := d.Bytes(ed25519.PublicKeySize)
+	c2 := d.G2()
+	c3 := d.Gt()
+	c4 := d.G1()
+	s := d.Bytes(ed25519.SignatureSize)
+	if err := d.Finish(); err != nil {
+		return decodeError(err)
+	}
+	ct.C1, ct.C2, ct.C3, ct.C4, ct.S = c1, c2, c3, c4, s
+	return nil
+}
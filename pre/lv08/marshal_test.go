@@ -0,0 +1,91 @@
+package lv08
+
+import (
+	"encoding"
+	"errors"
+	"testing"
+
+	"github.com/etclab/ncircl/util/binaryx"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+	rk := ReEncryptionKeyGen(pp, aliceSK, bobPK)
+
+	msg := blspairing.NewRandomGt()
+	ct2 := Encrypt2(pp, alicePK, msg)
+
+	roundTrip := func(t *testing.T, in encoding.BinaryMarshaler, out encoding.BinaryUnmarshaler) {
+		data, err := in.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary failed: %v", err)
+		}
+		if err := out.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+	}
+
+	gotPK := new(PublicKey)
+	roundTrip(t, bobPK, gotPK)
+	if !gotPK.Y1.IsEqual(bobPK.Y1) || !gotPK.Y2.IsEqual(bobPK.Y2) {
+		t.Fatal("PublicKey did not round-trip")
+	}
+
+	gotSK := new(PrivateKey)
+	roundTrip(t, bobSK, gotSK)
+	if gotSK.X.IsEqual(bobSK.X) != 1 {
+		t.Fatal("PrivateKey did not round-trip")
+	}
+
+	gotRK := new(ReEncryptionKey)
+	roundTrip(t, rk, gotRK)
+	if !gotRK.RK.IsEqual(rk.RK) || !gotRK.DelegatorPK.Y2.IsEqual(alicePK.Y2) {
+		t.Fatal("ReEncryptionKey did not round-trip")
+	}
+
+	gotCT2 := new(Ciphertext2)
+	roundTrip(t, ct2, gotCT2)
+	ct1, err := ReEncrypt(pp, gotRK, gotCT2)
+	if err != nil {
+		t.Fatalf("ReEncrypt failed: %v", err)
+	}
+
+	gotCT1 := new(Ciphertext1)
+	roundTrip(t, ct1, gotCT1)
+	got, err := Decrypt1(pp, gotSK, gotCT1)
+	if err != nil {
+		t.Fatalf("Decrypt1 failed: %v", err)
+	}
+	if !got.IsEqual(msg) {
+		t.Fatal("ciphertexts did not round-trip")
+	}
+}
+
+func TestUnmarshal_strict(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, _ := KeyGen(pp)
+	data, err := Encrypt2(pp, alicePK, blspairing.NewRandomGt()).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	if err := new(Ciphertext2).UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, binaryx.ErrTruncated) {
+		t.Fatalf("expected ErrTruncated, but got %v", err)
+	}
+	if err := new(Ciphertext2).UnmarshalBinary(append(data, 0)); !errors.Is(err, binaryx.ErrTrailingData) {
+		t.Fatalf("expected ErrTrailingData, but got %v", err)
+	}
+	// a second-level ciphertext is not a first-level one
+	if err := new(Ciphertext1).UnmarshalBinary(data); !errors.Is(err, binaryx.ErrInvalidHeader) {
+		t.Fatalf("expected ErrInvalidHeader, but got %v", err)
+	}
+
+	// C2 immediately follows the 32-byte signature verification key
+	data[binaryx.HeaderSize+32+10] ^= 1
+	if err := new(Ciphertext2).UnmarshalBinary(data); !errors.Is(err, ErrInvalidEncoding) || !errors.Is(err, binaryx.ErrInvalidElement) {
+		t.Fatalf("expected ErrInvalidElement, but got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+// Package n18 implements Umbral, the threshold proxy re-encryption scheme
+// from the [paper]:
+//
+//	@techreport{18-nucypher-umbral,
+//	    title = {Umbral: A Threshold Proxy Re-Encryption Scheme},
+//	    author = {Nu{\~n}ez, David},
+//	    institution = {NuCypher},
+//	    year = {2018},
+//	}
+//
+// Alice splits a re-encryption key for Bob into n key fragments (kfrags),
+// any t of which suffice.  Each proxy holds one kfrag, and, given a capsule
+// (the key encapsulation of a ciphertext), produces a capsule fragment
+// (cfrag) together with a non-interactive proof that the cfrag was computed
+// correctly from the capsule and that proxy's kfrag.  Bob verifies each
+// cfrag, discards the invalid ones, and combines t valid cfrags to open the
+// capsule.
+//
+// # Changes from Paper
+// The paper is agnostic to the prime-order group; this package uses G1 of
+// BLS12-381, and no pairings are needed.  Like the paper, the package
+// includes a DEM (AES-256-GCM) so that arbitrary byte payloads can be
+// encrypted.
+//
+// # Properties
+//   - unidirectional
+//   - single-hop
+//   - threshold (t-of-n) re-encryption with publicly verifiable fragments
+//   - CCA-secure capsules (see [Capsule.Check])
+//
+// [paper]: https://github.com/nucypher/umbral-doc/blob/master/umbral-doc.pdf
+package n18
@@ -0,0 +1,47 @@
+package n18_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/etclab/ncircl/pre/n18"
+)
+
+// Example shows Alice delegating decryption to Bob through three proxies,
+// any two of which suffice.
+func Example() {
+	pp := n18.NewPublicParams()
+
+	alicePK, aliceSK := n18.KeyGen(pp)
+	bobPK, bobSK := n18.KeyGen(pp)
+
+	kfrags, err := n18.ReEncryptionKeyGen(pp, aliceSK, bobPK, 2, 3)
+	if err != nil {
+		log.Fatalf("ReEncryptionKeyGen failed: %v", err)
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	ct, err := n18.Encrypt(pp, alicePK, msg)
+	if err != nil {
+		log.Fatalf("Encrypt failed: %v", err)
+	}
+
+	// Each proxy re-encrypts the capsule with its kfrag.
+	var cfrags []*n18.CapsuleFragment
+	for _, kf := range kfrags[1:] {
+		cf, err := n18.ReEncrypt(pp, kf, ct.Capsule)
+		if err != nil {
+			log.Fatalf("ReEncrypt failed: %v", err)
+		}
+		cfrags = append(cfrags, cf)
+	}
+
+	got, err := n18.DecryptFragments(pp, bobSK, alicePK, ct, cfrags)
+	if err != nil {
+		log.Fatalf("DecryptFragments failed: %v", err)
+	}
+
+	fmt.Println(string(got))
+	// Output:
+	// The quick brown fox jumps over the lazy dog.
+}
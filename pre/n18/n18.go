@@ -0,0 +1,498 @@
+package n18
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/mu"
+	"github.com/etclab/ncircl/util/aesx"
+	"github.com/etclab/ncircl/util/blspairing"
+	"golang.org/x/crypto/hkdf"
+)
+
+var (
+	ErrInvalidThreshold        = errors.New("n18: threshold must satisfy 1 <= t <= n")
+	ErrInvalidCapsule          = errors.New("n18: invalid capsule")
+	ErrInvalidKeyFragment      = errors.New("n18: invalid key fragment")
+	ErrInvalidCapsuleFragment  = errors.New("n18: invalid capsule fragment")
+	ErrNoCapsuleFragments      = errors.New("n18: no capsule fragments")
+	ErrDuplicateFragment       = errors.New("n18: duplicate capsule fragment")
+	ErrCapsuleFragmentMismatch = errors.New("n18: capsule fragments are from different re-encryption keys")
+	ErrInvalidPayload          = errors.New("n18: invalid payload")
+)
+
+// Domain separation tags for the paper's hash functions H2 through H6 and
+// for the hash in the cfrag correctness proof.
+const (
+	tagH2    = "n18-H2"
+	tagH3    = "n18-H3"
+	tagH4    = "n18-H4"
+	tagH5    = "n18-H5"
+	tagH6    = "n18-H6"
+	tagProof = "n18-proof"
+)
+
+func hashToScalar(tag string, parts ...[]byte) *bls.Scalar {
+	buf := []byte(tag)
+	for _, p := range parts {
+		buf = append(buf, p...)
+	}
+	return blspairing.HashBytesToScalar(buf)
+}
+
+func kdf(p *bls.G1) []byte {
+	kdf := hkdf.New(sha256.New, p.Bytes(), nil, nil)
+
+	key := make([]byte, blspairing.Aes256KeySize)
+	_, err := io.ReadFull(kdf, key)
+	if err != nil {
+		mu.Panicf("io.ReadFull failed: %v", err)
+	}
+
+	return key
+}
+
+type PublicParams struct {
+	G *bls.G1
+	U *bls.G1 // second generator, used to commit to kfrags
+}
+
+func NewPublicParams() *PublicParams {
+	pp := new(PublicParams)
+
+	pp.G = bls.G1Generator()
+	pp.U = blspairing.HashBytesToG1([]byte("n18-U"), nil)
+
+	return pp
+}
+
+type PrivateKey struct {
+	X *bls.Scalar
+}
+
+func (sk *PrivateKey) PublicKey(pp *PublicParams) *PublicKey {
+	pk := new(PublicKey)
+	pk.Y = new(bls.G1)
+	pk.Y.ScalarMult(sk.X, pp.G)
+	return pk
+}
+
+type PublicKey struct {
+	Y *bls.G1
+}
+
+func KeyGen(pp *PublicParams) (*PublicKey, *PrivateKey) {
+	sk := new(PrivateKey)
+	sk.X = blspairing.NewRandomScalar()
+	return sk.PublicKey(pp), sk
+}
+
+// Capsule is the key encapsulation of a ciphertext.  Only the capsule is
+// sent to the proxies.
+type Capsule struct {
+	E *bls.G1
+	V *bls.G1
+	S *bls.Scalar
+}
+
+func (c *Capsule) Bytes() []byte {
+	m := make([]byte, 0, 256)
+	m = append(m, c.E.Bytes()...)
+	m = append(m, c.V.Bytes()...)
+	m = append(m, blspairing.ScalarToBytes(c.S)...)
+	return m
+}
+
+// Check verifies that g^S = V * E^H2(E, V).
+func (c *Capsule) Check(pp *PublicParams) error {
+	h := hashToScalar(tagH2, c.E.Bytes(), c.V.Bytes())
+
+	lhs := new(bls.G1)
+	lhs.ScalarMult(c.S, pp.G)
+
+	rhs := new(bls.G1)
+	rhs.ScalarMult(h, c.E)
+	rhs.Add(rhs, c.V)
+
+	if !lhs.IsEqual(rhs) {
+		return ErrInvalidCapsule
+	}
+	return nil
+}
+
+// Encapsulate returns a fresh AES-256 key and the capsule that encapsulates
+// it for pk.
+func Encapsulate(pp *PublicParams, pk *PublicKey) ([]byte, *Capsule) {
+	r := blspairing.NewRandomScalar()
+	u := blspairing.NewRandomScalar()
+
+	E := new(bls.G1)
+	E.ScalarMult(r, pp.G)
+	V := new(bls.G1)
+	V.ScalarMult(u, pp.G)
+
+	h := hashToScalar(tagH2, E.Bytes(), V.Bytes())
+	s := new(bls.Scalar)
+	s.Mul(r, h)
+	s.Add(s, u)
+
+	ru := new(bls.Scalar)
+	ru.Add(r, u)
+	shared := new(bls.G1)
+	shared.ScalarMult(ru, pk.Y)
+
+	return kdf(shared), &Capsule{E: E, V: V, S: s}
+}
+
+// Decapsulate opens a capsule that was encapsulated directly for sk.
+func Decapsulate(pp *PublicParams, sk *PrivateKey, capsule *Capsule) ([]byte, error) {
+	if err := capsule.Check(pp); err != nil {
+		return nil, err
+	}
+
+	shared := new(bls.G1)
+	shared.Add(capsule.E, capsule.V)
+	shared.ScalarMult(sk.X, shared)
+
+	return kdf(shared), nil
+}
+
+// KeyFragment is one of the n shares of Alice's re-encryption key for Bob.
+// (Z1, Z2) is Alice's Schnorr signature over (Id, U1, XA), which lets both
+// the proxy and Bob check that the fragment came from Alice.
+type KeyFragment struct {
+	Id *bls.Scalar
+	RK *bls.Scalar
+	XA *bls.G1
+	U1 *bls.G1
+	Z1 *bls.Scalar
+	Z2 *bls.Scalar
+}
+
+func kfragChallenge(y *bls.G1, id *bls.Scalar, alicePK, bobPK *PublicKey, u1, xa *bls.G1) *bls.Scalar {
+	return hashToScalar(tagH4, y.Bytes(), blspairing.ScalarToBytes(id), alicePK.Y.Bytes(),
+		bobPK.Y.Bytes(), u1.Bytes(), xa.Bytes())
+}
+
+// checkKFragSignature recomputes Y = g^z2 * pkA^z1 and checks that z1 is the
+// challenge for Y.
+func checkKFragSignature(pp *PublicParams, alicePK, bobPK *PublicKey, id *bls.Scalar, u1, xa *bls.G1, z1, z2 *bls.Scalar) bool {
+	y := new(bls.G1)
+	y.ScalarMult(z2, pp.G)
+	var tmp bls.G1
+	tmp.ScalarMult(z1, alicePK.Y)
+	y.Add(y, &tmp)
+
+	z := kfragChallenge(y, id, alicePK, bobPK, u1, xa)
+	return z.IsEqual(z1) == 1
+}
+
+// ReEncryptionKeyGen splits Alice's re-encryption key for Bob into n
+// fragments, any threshold of which are needed to re-encrypt.
+func ReEncryptionKeyGen(pp *PublicParams, aliceSK *PrivateKey, bobPK *PublicKey, threshold, n int) ([]*KeyFragment, error) {
+	if threshold < 1 || threshold > n {
+		return nil, ErrInvalidThreshold
+	}
+
+	alicePK := aliceSK.PublicKey(pp)
+
+	xa := blspairing.NewRandomScalar()
+	XA := new(bls.G1)
+	XA.ScalarMult(xa, pp.G)
+
+	dh := new(bls.G1)
+	dh.ScalarMult(xa, bobPK.Y)
+	d := hashToScalar(tagH3, XA.Bytes(), bobPK.Y.Bytes(), dh.Bytes())
+
+	// f(x) = f0 + f1*x + ... + f_{t-1}*x^{t-1}, with f0 = a/d
+	coeffs := make([]*bls.Scalar, threshold)
+	coeffs[0] = new(bls.Scalar)
+	coeffs[0].Inv(d)
+	coeffs[0].Mul(coeffs[0], aliceSK.X)
+	for i := 1; i < threshold; i++ {
+		coeffs[i] = blspairing.NewRandomScalar()
+	}
+
+	dh.ScalarMult(aliceSK.X, bobPK.Y)
+	D := hashToScalar(tagH6, alicePK.Y.Bytes(), bobPK.Y.Bytes(), dh.Bytes())
+
+	kfrags := make([]*KeyFragment, n)
+	for i := 0; i < n; i++ {
+		id := blspairing.NewRandomScalar()
+		sx := hashToScalar(tagH5, blspairing.ScalarToBytes(id), blspairing.ScalarToBytes(D))
+
+		// Horner's method
+		rk := blspairing.CloneScalar(coeffs[threshold-1])
+		for j := threshold - 2; j >= 0; j-- {
+			rk.Mul(rk, sx)
+			rk.Add(rk, coeffs[j])
+		}
+
+		U1 := new(bls.G1)
+		U1.ScalarMult(rk, pp.U)
+
+		y := blspairing.NewRandomScalar()
+		Y := new(bls.G1)
+		Y.ScalarMult(y, pp.G)
+		z1 := kfragChallenge(Y, id, alicePK, bobPK, U1, XA)
+		z2 := new(bls.Scalar)
+		z2.Mul(aliceSK.X, z1)
+		z2.Sub(y, z2)
+
+		kfrags[i] = &KeyFragment{
+			Id: id,
+			RK: rk,
+			XA: blspairing.CloneG1(XA),
+			U1: U1,
+			Z1: z1,
+			Z2: z2,
+		}
+	}
+
+	return kfrags, nil
+}
+
+// Verify lets a proxy check that the kfrag was issued by Alice for Bob, and
+// that U1 commits to RK.
+func (kf *KeyFragment) Verify(pp *PublicParams, alicePK, bobPK *PublicKey) error {
+	u1 := new(bls.G1)
+	u1.ScalarMult(kf.RK, pp.U)
+	if !u1.IsEqual(kf.U1) {
+		return ErrInvalidKeyFragment
+	}
+
+	if !checkKFragSignature(pp, alicePK, bobPK, kf.Id, kf.U1, kf.XA, kf.Z1, kf.Z2) {
+		return ErrInvalidKeyFragment
+	}
+
+	return nil
+}
+
+// CorrectnessProof is a proof of knowledge of RK such that E1 = E^RK,
+// V1 = V^RK and U1 = U^RK.  It carries the kfrag's U1 and signature so that
+// Bob can tie the proof to Alice.
+type CorrectnessProof struct {
+	E2 *bls.G1
+	V2 *bls.G1
+	U2 *bls.G1
+	U1 *bls.G1
+	Z1 *bls.Scalar
+	Z2 *bls.Scalar
+	Z3 *bls.Scalar
+}
+
+// CapsuleFragment is a proxy's share of the re-encryption of a capsule.
+type CapsuleFragment struct {
+	E1    *bls.G1
+	V1    *bls.G1
+	Id    *bls.Scalar
+	XA    *bls.G1
+	Proof *CorrectnessProof
+}
+
+func proofChallenge(pp *PublicParams, capsule *Capsule, e1, e2, v1, v2, u1, u2 *bls.G1) *bls.Scalar {
+	return hashToScalar(tagProof, capsule.E.Bytes(), e1.Bytes(), e2.Bytes(),
+		capsule.V.Bytes(), v1.Bytes(), v2.Bytes(), pp.U.Bytes(), u1.Bytes(), u2.Bytes())
+}
+
+// ReEncrypt is run by a proxy holding one kfrag.  It returns
+// [ErrInvalidCapsule] if the capsule is malformed.
+func ReEncrypt(pp *PublicParams, kf *KeyFragment, capsule *Capsule) (*CapsuleFragment, error) {
+	if err := capsule.Check(pp); err != nil {
+		return nil, err
+	}
+
+	E1 := new(bls.G1)
+	E1.ScalarMult(kf.RK, capsule.E)
+	V1 := new(bls.G1)
+	V1.ScalarMult(kf.RK, capsule.V)
+
+	t := blspairing.NewRandomScalar()
+	E2 := new(bls.G1)
+	E2.ScalarMult(t, capsule.E)
+	V2 := new(bls.G1)
+	V2.ScalarMult(t, capsule.V)
+	U2 := new(bls.G1)
+	U2.ScalarMult(t, pp.U)
+
+	h := proofChallenge(pp, capsule, E1, E2, V1, V2, kf.U1, U2)
+	z3 := new(bls.Scalar)
+	z3.Mul(h, kf.RK)
+	z3.Add(z3, t)
+
+	return &CapsuleFragment{
+		E1: E1,
+		V1: V1,
+		Id: blspairing.CloneScalar(kf.Id),
+		XA: blspairing.CloneG1(kf.XA),
+		Proof: &CorrectnessProof{
+			E2: E2,
+			V2: V2,
+			U2: U2,
+			U1: blspairing.CloneG1(kf.U1),
+			Z1: blspairing.CloneScalar(kf.Z1),
+			Z2: blspairing.CloneScalar(kf.Z2),
+			Z3: z3,
+		},
+	}, nil
+}
+
+// Verify lets Bob check that the cfrag was correctly computed from capsule
+// and from a kfrag that Alice issued for him.
+func (cf *CapsuleFragment) Verify(pp *PublicParams, capsule *Capsule, alicePK, bobPK *PublicKey) error {
+	proof := cf.Proof
+
+	if !checkKFragSignature(pp, alicePK, bobPK, cf.Id, proof.U1, cf.XA, proof.Z1, proof.Z2) {
+		return ErrInvalidCapsuleFragment
+	}
+
+	h := proofChallenge(pp, capsule, cf.E1, proof.E2, cf.V1, proof.V2, proof.U1, proof.U2)
+
+	// base^z3 == commit * value^h
+	check := func(base, value, commit *bls.G1) bool {
+		lhs := new(bls.G1)
+		lhs.ScalarMult(proof.Z3, base)
+		rhs := new(bls.G1)
+		rhs.ScalarMult(h, value)
+		rhs.Add(rhs, commit)
+		return lhs.IsEqual(rhs)
+	}
+
+	if !check(capsule.E, cf.E1, proof.E2) ||
+		!check(capsule.V, cf.V1, proof.V2) ||
+		!check(pp.U, proof.U1, proof.U2) {
+		return ErrInvalidCapsuleFragment
+	}
+
+	return nil
+}
+
+// DecapsulateFragments combines cfrags into the capsule's key.  Each cfrag is
+// verified first, and the function fails with [ErrInvalidCapsuleFragment] on
+// the first one that does not verify; callers that want to tolerate
+// misbehaving proxies should filter the cfrags with
+// [CapsuleFragment.Verify].  If fewer than the threshold number of cfrags are
+// given, the returned key is simply wrong.
+func DecapsulateFragments(pp *PublicParams, bobSK *PrivateKey, alicePK *PublicKey, capsule *Capsule, cfrags []*CapsuleFragment) ([]byte, error) {
+	if len(cfrags) == 0 {
+		return nil, ErrNoCapsuleFragments
+	}
+
+	if err := capsule.Check(pp); err != nil {
+		return nil, err
+	}
+
+	bobPK := bobSK.PublicKey(pp)
+	XA := cfrags[0].XA
+	for _, cf := range cfrags {
+		if !cf.XA.IsEqual(XA) {
+			return nil, ErrCapsuleFragmentMismatch
+		}
+		if err := cf.Verify(pp, capsule, alicePK, bobPK); err != nil {
+			return nil, err
+		}
+	}
+
+	dh := new(bls.G1)
+	dh.ScalarMult(bobSK.X, alicePK.Y)
+	D := hashToScalar(tagH6, alicePK.Y.Bytes(), bobPK.Y.Bytes(), dh.Bytes())
+
+	xs := make([]*bls.Scalar, len(cfrags))
+	for i, cf := range cfrags {
+		xs[i] = hashToScalar(tagH5, blspairing.ScalarToBytes(cf.Id), blspairing.ScalarToBytes(D))
+		for j := 0; j < i; j++ {
+			if xs[i].IsEqual(xs[j]) == 1 {
+				return nil, ErrDuplicateFragment
+			}
+		}
+	}
+
+	E := blspairing.NewG1Identity()
+	V := blspairing.NewG1Identity()
+	var tmp bls.G1
+	for i, cf := range cfrags {
+		lambda := lagrangeAtZero(xs, i)
+		tmp.ScalarMult(lambda, cf.E1)
+		E.Add(E, &tmp)
+		tmp.ScalarMult(lambda, cf.V1)
+		V.Add(V, &tmp)
+	}
+
+	dh.ScalarMult(bobSK.X, XA)
+	d := hashToScalar(tagH3, XA.Bytes(), bobPK.Y.Bytes(), dh.Bytes())
+
+	shared := new(bls.G1)
+	shared.Add(E, V)
+	shared.ScalarMult(d, shared)
+
+	return kdf(shared), nil
+}
+
+// lagrangeAtZero returns the Lagrange coefficient for xs[i], evaluated at 0.
+func lagrangeAtZero(xs []*bls.Scalar, i int) *bls.Scalar {
+	num := blspairing.NewScalarOne()
+	den := blspairing.NewScalarOne()
+	var tmp bls.Scalar
+	for j, x := range xs {
+		if j == i {
+			continue
+		}
+		num.Mul(num, x)
+		tmp.Sub(x, xs[i])
+		den.Mul(den, &tmp)
+	}
+	den.Inv(den)
+	num.Mul(num, den)
+	return num
+}
+
+// Ciphertext is a capsule together with the AES-256-GCM encryption of the
+// payload under the encapsulated key.  The capsule is bound to the payload as
+// associated data.
+type Ciphertext struct {
+	Capsule *Capsule
+	Payload []byte
+}
+
+func Encrypt(pp *PublicParams, pk *PublicKey, msg []byte) (*Ciphertext, error) {
+	key, capsule := Encapsulate(pp, pk)
+	payload, err := aesx.EncryptGCM(key, msg, capsule.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ciphertext{
+		Capsule: capsule,
+		Payload: payload,
+	}, nil
+}
+
+func Decrypt(pp *PublicParams, sk *PrivateKey, ct *Ciphertext) ([]byte, error) {
+	key, err := Decapsulate(pp, sk, ct.Capsule)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := aesx.DecryptGCM(key, ct.Payload, ct.Capsule.Bytes())
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	return msg, nil
+}
+
+// DecryptFragments decrypts a ciphertext for Alice with cfrags that proxies
+// computed from the ciphertext's capsule.
+func DecryptFragments(pp *PublicParams, bobSK *PrivateKey, alicePK *PublicKey, ct *Ciphertext, cfrags []*CapsuleFragment) ([]byte, error) {
+	key, err := DecapsulateFragments(pp, bobSK, alicePK, ct.Capsule, cfrags)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := aesx.DecryptGCM(key, ct.Payload, ct.Capsule.Bytes())
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	return msg, nil
+}
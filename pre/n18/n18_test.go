@@ -0,0 +1,285 @@
+package n18
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestEncapsulateDecapsulate(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+
+	key, capsule := Encapsulate(pp, alicePK)
+	got, err := Decapsulate(pp, aliceSK, capsule)
+	if err != nil {
+		t.Fatalf("Decapsulate failed: %v", err)
+	}
+	if !bytes.Equal(key, got) {
+		t.Fatal("Decapsulate did not produce the encapsulated key")
+	}
+}
+
+func TestReEncryptDecapsulateFragments(t *testing.T) {
+	trials := []struct {
+		threshold int
+		n         int
+	}{
+		{1, 1},
+		{1, 3},
+		{2, 3},
+		{3, 5},
+		{5, 5},
+	}
+
+	for _, trial := range trials {
+		t.Run(fmt.Sprintf("%d-of-%d", trial.threshold, trial.n), func(t *testing.T) {
+			pp := NewPublicParams()
+			alicePK, aliceSK := KeyGen(pp)
+			bobPK, bobSK := KeyGen(pp)
+
+			kfrags, err := ReEncryptionKeyGen(pp, aliceSK, bobPK, trial.threshold, trial.n)
+			if err != nil {
+				t.Fatalf("ReEncryptionKeyGen failed: %v", err)
+			}
+
+			key, capsule := Encapsulate(pp, alicePK)
+
+			// use the last t kfrags, to show that any subset works
+			var cfrags []*CapsuleFragment
+			for _, kf := range kfrags[trial.n-trial.threshold:] {
+				if err := kf.Verify(pp, alicePK, bobPK); err != nil {
+					t.Fatalf("KeyFragment.Verify failed: %v", err)
+				}
+				cf, err := ReEncrypt(pp, kf, capsule)
+				if err != nil {
+					t.Fatalf("ReEncrypt failed: %v", err)
+				}
+				cfrags = append(cfrags, cf)
+			}
+
+			got, err := DecapsulateFragments(pp, bobSK, alicePK, capsule, cfrags)
+			if err != nil {
+				t.Fatalf("DecapsulateFragments failed: %v", err)
+			}
+			if !bytes.Equal(key, got) {
+				t.Fatal("DecapsulateFragments did not produce the encapsulated key")
+			}
+		})
+	}
+}
+
+func TestDecapsulateFragments_belowThreshold(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+
+	kfrags, err := ReEncryptionKeyGen(pp, aliceSK, bobPK, 3, 5)
+	if err != nil {
+		t.Fatalf("ReEncryptionKeyGen failed: %v", err)
+	}
+
+	key, capsule := Encapsulate(pp, alicePK)
+	var cfrags []*CapsuleFragment
+	for _, kf := range kfrags[:2] {
+		cf, err := ReEncrypt(pp, kf, capsule)
+		if err != nil {
+			t.Fatalf("ReEncrypt failed: %v", err)
+		}
+		cfrags = append(cfrags, cf)
+	}
+
+	got, err := DecapsulateFragments(pp, bobSK, alicePK, capsule, cfrags)
+	if err != nil {
+		t.Fatalf("DecapsulateFragments failed: %v", err)
+	}
+	if bytes.Equal(key, got) {
+		t.Fatal("DecapsulateFragments recovered the key from fewer than threshold cfrags")
+	}
+
+	_, err = DecapsulateFragments(pp, bobSK, alicePK, capsule, []*CapsuleFragment{cfrags[0], cfrags[0]})
+	if err != ErrDuplicateFragment {
+		t.Fatalf("expected ErrDuplicateFragment, but got %v", err)
+	}
+}
+
+func TestCapsuleFragmentVerify_misbehavingProxy(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+
+	kfrags, err := ReEncryptionKeyGen(pp, aliceSK, bobPK, 2, 3)
+	if err != nil {
+		t.Fatalf("ReEncryptionKeyGen failed: %v", err)
+	}
+
+	_, capsule := Encapsulate(pp, alicePK)
+	cf, err := ReEncrypt(pp, kfrags[0], capsule)
+	if err != nil {
+		t.Fatalf("ReEncrypt failed: %v", err)
+	}
+	if err := cf.Verify(pp, capsule, alicePK, bobPK); err != nil {
+		t.Fatalf("CapsuleFragment.Verify failed on an honest cfrag: %v", err)
+	}
+
+	// proxy uses a different re-encryption key
+	bad := *kfrags[1]
+	bad.RK = blspairing.NewRandomScalar()
+	badCF, err := ReEncrypt(pp, &bad, capsule)
+	if err != nil {
+		t.Fatalf("ReEncrypt failed: %v", err)
+	}
+	if err := badCF.Verify(pp, capsule, alicePK, bobPK); err != ErrInvalidCapsuleFragment {
+		t.Fatalf("expected ErrInvalidCapsuleFragment for a wrong key, but got %v", err)
+	}
+	if err := bad.Verify(pp, alicePK, bobPK); err != ErrInvalidKeyFragment {
+		t.Fatalf("expected ErrInvalidKeyFragment, but got %v", err)
+	}
+
+	// proxy tampers with E1
+	badCF, err = ReEncrypt(pp, kfrags[1], capsule)
+	if err != nil {
+		t.Fatalf("ReEncrypt failed: %v", err)
+	}
+	badCF.E1.Add(badCF.E1, pp.G)
+	if err := badCF.Verify(pp, capsule, alicePK, bobPK); err != ErrInvalidCapsuleFragment {
+		t.Fatalf("expected ErrInvalidCapsuleFragment for a tampered cfrag, but got %v", err)
+	}
+
+	_, err = DecapsulateFragments(pp, bobSK, alicePK, capsule, []*CapsuleFragment{cf, badCF})
+	if err != ErrInvalidCapsuleFragment {
+		t.Fatalf("expected ErrInvalidCapsuleFragment, but got %v", err)
+	}
+
+	// cfrag is valid, but for some other capsule
+	_, other := Encapsulate(pp, alicePK)
+	if err := cf.Verify(pp, other, alicePK, bobPK); err != ErrInvalidCapsuleFragment {
+		t.Fatalf("expected ErrInvalidCapsuleFragment for another capsule, but got %v", err)
+	}
+}
+
+func TestReEncrypt_invalidCapsule(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, _ := KeyGen(pp)
+
+	kfrags, err := ReEncryptionKeyGen(pp, aliceSK, bobPK, 1, 1)
+	if err != nil {
+		t.Fatalf("ReEncryptionKeyGen failed: %v", err)
+	}
+
+	_, capsule := Encapsulate(pp, alicePK)
+	capsule.S = blspairing.NewRandomScalar()
+	if _, err := ReEncrypt(pp, kfrags[0], capsule); err != ErrInvalidCapsule {
+		t.Fatalf("expected ErrInvalidCapsule, but got %v", err)
+	}
+}
+
+func TestEncryptDecryptFragments(t *testing.T) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	ct, err := Encrypt(pp, alicePK, msg)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	got, err := Decrypt(pp, aliceSK, ct)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if !bytes.Equal(msg, got) {
+		t.Fatal("Decrypt did not produce the original message")
+	}
+
+	kfrags, err := ReEncryptionKeyGen(pp, aliceSK, bobPK, 2, 3)
+	if err != nil {
+		t.Fatalf("ReEncryptionKeyGen failed: %v", err)
+	}
+	var cfrags []*CapsuleFragment
+	for _, kf := range kfrags[:2] {
+		cf, err := ReEncrypt(pp, kf, ct.Capsule)
+		if err != nil {
+			t.Fatalf("ReEncrypt failed: %v", err)
+		}
+		cfrags = append(cfrags, cf)
+	}
+
+	got, err = DecryptFragments(pp, bobSK, alicePK, ct, cfrags)
+	if err != nil {
+		t.Fatalf("DecryptFragments failed: %v", err)
+	}
+	if !bytes.Equal(msg, got) {
+		t.Fatal("DecryptFragments did not produce the original message")
+	}
+}
+
+func TestReEncryptionKeyGen_invalidThreshold(t *testing.T) {
+	pp := NewPublicParams()
+	_, aliceSK := KeyGen(pp)
+	bobPK, _ := KeyGen(pp)
+
+	for _, tn := range [][2]int{{0, 3}, {4, 3}} {
+		if _, err := ReEncryptionKeyGen(pp, aliceSK, bobPK, tn[0], tn[1]); err != ErrInvalidThreshold {
+			t.Fatalf("expected ErrInvalidThreshold for t=%d, n=%d, but got %v", tn[0], tn[1], err)
+		}
+	}
+}
+
+func BenchmarkReEncryptionKeyGen(b *testing.B) {
+	pp := NewPublicParams()
+	_, aliceSK := KeyGen(pp)
+	bobPK, _ := KeyGen(pp)
+	for b.Loop() {
+		_, err := ReEncryptionKeyGen(pp, aliceSK, bobPK, 3, 5)
+		if err != nil {
+			b.Fatalf("ReEncryptionKeyGen failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkReEncrypt(b *testing.B) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, _ := KeyGen(pp)
+	kfrags, err := ReEncryptionKeyGen(pp, aliceSK, bobPK, 3, 5)
+	if err != nil {
+		b.Fatalf("ReEncryptionKeyGen failed: %v", err)
+	}
+	_, capsule := Encapsulate(pp, alicePK)
+	for b.Loop() {
+		_, err := ReEncrypt(pp, kfrags[0], capsule)
+		if err != nil {
+			b.Fatalf("ReEncrypt failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkDecapsulateFragments(b *testing.B) {
+	pp := NewPublicParams()
+	alicePK, aliceSK := KeyGen(pp)
+	bobPK, bobSK := KeyGen(pp)
+	kfrags, err := ReEncryptionKeyGen(pp, aliceSK, bobPK, 3, 5)
+	if err != nil {
+		b.Fatalf("ReEncryptionKeyGen failed: %v", err)
+	}
+	_, capsule := Encapsulate(pp, alicePK)
+	var cfrags []*CapsuleFragment
+	for _, kf := range kfrags[:3] {
+		cf, err := ReEncrypt(pp, kf, capsule)
+		if err != nil {
+			b.Fatalf("ReEncrypt failed: %v", err)
+		}
+		cfrags = append(cfrags, cf)
+	}
+	for b.Loop() {
+		_, err := DecapsulateFragments(pp, bobSK, alicePK, capsule, cfrags)
+		if err != nil {
+			b.Fatalf("DecapsulateFragments failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package properties
+
+import (
+	"testing"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/pre/afgh05"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// afgh05 is unidirectional: an Alice-to-Bob key does not re-encrypt Bob's
+// ciphertexts for Alice, and neither do the obvious transformations of it.
+func TestAFGH05_unidirectional(t *testing.T) {
+	pp := afgh05.NewPublicParams()
+	alicePK, aliceSK := afgh05.KeyGen(pp)
+	bobPK, _ := afgh05.KeyGen(pp)
+	rkAliceToBob := afgh05.ReEncryptionKeyGen(pp, aliceSK, bobPK)
+
+	negated := blspairing.CloneG2(rkAliceToBob.RK)
+	negated.Neg()
+
+	sum := new(bls.G2)
+	sum.Add(rkAliceToBob.RK, alicePK.G2ToA)
+
+	candidates := map[string]*bls.G2{
+		"rkAliceToBob":         rkAliceToBob.RK,
+		"-rkAliceToBob":        negated,
+		"alicePK":              alicePK.G2ToA,
+		"bobPK":                bobPK.G2ToA,
+		"rkAliceToBob+alicePK": sum,
+	}
+
+	msg := blspairing.NewRandomGt()
+	ct1 := afgh05.Encrypt(pp, bobPK, msg)
+
+	for name, rk := range candidates {
+		t.Run(name, func(t *testing.T) {
+			ct2 := afgh05.ReEncrypt(pp, &afgh05.ReEncryptionKey{RK: rk}, ct1)
+			if afgh05.Decrypt2(pp, aliceSK, ct2).IsEqual(msg) {
+				t.Fatal("derived a working Bob-to-Alice re-encryption key")
+			}
+		})
+	}
+}
+
+// A re-encryption key is specific to the delegatee: Carol cannot decrypt
+// what was re-encrypted for Bob.
+func TestAFGH05_delegateeBinding(t *testing.T) {
+	pp := afgh05.NewPublicParams()
+	alicePK, aliceSK := afgh05.KeyGen(pp)
+	bobPK, _ := afgh05.KeyGen(pp)
+	_, carolSK := afgh05.KeyGen(pp)
+
+	msg := blspairing.NewRandomGt()
+	ct2 := afgh05.ReEncrypt(pp, afgh05.ReEncryptionKeyGen(pp, aliceSK, bobPK), afgh05.Encrypt(pp, alicePK, msg))
+	if afgh05.Decrypt2(pp, carolSK, ct2).IsEqual(msg) {
+		t.Fatal("a non-delegatee decrypted a re-encrypted ciphertext")
+	}
+}
+
+// Even colluding with the proxy, Bob cannot use a key for one epoch to read
+// Alice's ciphertexts from another epoch.
+func TestAFGH05_temporaryCollusion(t *testing.T) {
+	pp := afgh05.NewPublicParams()
+	alicePK, aliceSK := afgh05.TempKeyGen(pp)
+	_, bobSK := afgh05.TempKeyGen(pp)
+	rk := afgh05.TempReEncryptionKeyGen(pp, aliceSK, bobSK.EpochKey(1))
+
+	// rk = h_1^(b0*ar); Bob strips b0 to get h_1^ar
+	b0Inv := new(bls.Scalar)
+	b0Inv.Inv(bobSK.A0)
+	hToAR := new(bls.G2)
+	hToAR.ScalarMult(b0Inv, rk.RK)
+
+	decrypt := func(ct1 *afgh05.TempCiphertext1) *bls.Gt {
+		mask := bls.Pair(ct1.Beta, hToAR)
+		mask.Inv(mask)
+		m := new(bls.Gt)
+		m.Mul(ct1.Alpha, mask)
+		return m
+	}
+
+	msg := blspairing.NewRandomGt()
+	if !decrypt(afgh05.TempEncrypt(pp, alicePK, 1, msg)).IsEqual(msg) {
+		t.Fatal("sanity check failed: collusion did not decrypt the delegated epoch")
+	}
+	if decrypt(afgh05.TempEncrypt(pp, alicePK, 2, msg)).IsEqual(msg) {
+		t.Fatal("collusion decrypted a ciphertext from another epoch")
+	}
+}
+
+// Even colluding with the proxy, Bob cannot use a key for one condition to
+// read Alice's ciphertexts under another condition.
+func TestAFGH05_conditionalCollusion(t *testing.T) {
+	pp := afgh05.NewPublicParams()
+	alicePK, aliceSK := afgh05.KeyGen(pp)
+	bobPK, bobSK := afgh05.KeyGen(pp)
+	rk := afgh05.CondReEncryptionKeyGen(pp, aliceSK, bobPK, []byte("project=alpha"))
+
+	// rk = g2^(b/(a+H(w))); Bob strips b to get g2^(1/(a+H(w)))
+	bInv := new(bls.Scalar)
+	bInv.Inv(bobSK.A)
+	key := new(bls.G2)
+	key.ScalarMult(bInv, rk.RK)
+
+	decrypt := func(ct1 *afgh05.CondCiphertext1) *bls.Gt {
+		mask := bls.Pair(ct1.Beta, key)
+		mask.Inv(mask)
+		m := new(bls.Gt)
+		m.Mul(ct1.Alpha, mask)
+		return m
+	}
+
+	msg := blspairing.NewRandomGt()
+	if !decrypt(afgh05.CondEncrypt(pp, alicePK, []byte("project=alpha"), msg)).IsEqual(msg) {
+		t.Fatal("sanity check failed: collusion did not decrypt the delegated condition")
+	}
+	if decrypt(afgh05.CondEncrypt(pp, alicePK, []byte("project=beta"), msg)).IsEqual(msg) {
+		t.Fatal("collusion decrypted a ciphertext under another condition")
+	}
+	ct1 := afgh05.Encrypt(pp, alicePK, msg)
+	if decrypt(&afgh05.CondCiphertext1{Alpha: ct1.Alpha, Beta: ct1.Beta}).IsEqual(msg) {
+		t.Fatal("collusion decrypted an unconditional ciphertext")
+	}
+}
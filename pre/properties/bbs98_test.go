@@ -0,0 +1,59 @@
+package properties
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"testing"
+
+	"github.com/etclab/ncircl/ecc"
+	"github.com/etclab/ncircl/pre/bbs98"
+)
+
+// bbs98 is bidirectional: the inverse of an Alice-to-Bob key re-encrypts
+// from Bob to Alice.
+func TestBBS98_bidirectional(t *testing.T) {
+	pp := bbs98.NewPublicParams(elliptic.P256())
+	_, aliceSK := bbs98.KeyGen(pp)
+	bobPK, bobSK := bbs98.KeyGen(pp)
+	rk := bbs98.ReEncryptionKeyGen(pp, aliceSK, bobSK)
+
+	reverse := &bbs98.ReEncryptionKey{RK: new(big.Int).ModInverse(rk.RK, pp.Curve.Params().N)}
+
+	msg := ecc.NewRandomPoint(pp.Curve)
+	ct, err := bbs98.Encrypt(pp, bobPK, msg)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	bbs98.ReEncrypt(pp, reverse, ct)
+	if !bbs98.Decrypt(pp, aliceSK, ct).Equal(msg) {
+		t.Fatal("the inverted key did not re-encrypt from Bob to Alice")
+	}
+}
+
+// bbs98 is not collusion-resistant: the proxy and Bob together recover
+// Alice's private key.  This documents an inherent weakness rather than a
+// property.
+func TestBBS98_collusionRecoversDelegatorKey(t *testing.T) {
+	pp := bbs98.NewPublicParams(elliptic.P256())
+	alicePK, aliceSK := bbs98.KeyGen(pp)
+	_, bobSK := bbs98.KeyGen(pp)
+	rk := bbs98.ReEncryptionKeyGen(pp, aliceSK, bobSK)
+
+	// rk = b/a, so a = b/rk
+	n := pp.Curve.Params().N
+	k := new(big.Int).ModInverse(rk.RK, n)
+	k.Mul(k, bobSK.K)
+	k.Mod(k, n)
+	if k.Cmp(aliceSK.K) != 0 {
+		t.Fatal("collusion did not recover the delegator's key")
+	}
+
+	msg := ecc.NewRandomPoint(pp.Curve)
+	ct, err := bbs98.Encrypt(pp, alicePK, msg)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !bbs98.Decrypt(pp, &bbs98.PrivateKey{K: k}, ct).Equal(msg) {
+		t.Fatal("the recovered key did not decrypt")
+	}
+}
@@ -0,0 +1,99 @@
+package properties
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/mu"
+	"github.com/etclab/ncircl/pre/ch07"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestCH07_maulCiphertext(t *testing.T) {
+	pp := ch07.NewPublicParams()
+	alicePK, aliceSK := ch07.KeyGen(pp)
+	bobPK, bobSK := ch07.KeyGen(pp)
+	rk := ch07.ReEncryptionKeyGen(pp, aliceSK, bobSK)
+
+	msg := blspairing.NewRandomGt()
+	delta := blspairing.NewRandomGt()
+
+	resign := func(ct *ch07.Ciphertext) {
+		svk, ssk, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			mu.Panicf("ed25519.GenerateKey failed: %v", err)
+		}
+		ct.A = svk
+		ct.S = ed25519.Sign(ssk, ct.MessageToSign())
+	}
+
+	mauls := []struct {
+		name string
+		maul func(ct *ch07.Ciphertext)
+		err  error
+	}{
+		{"B", func(ct *ch07.Ciphertext) { ct.B.Add(ct.B, pp.G1) }, ch07.ErrInvalidCiphertext},
+		{"C", func(ct *ch07.Ciphertext) { ct.C.Mul(ct.C, delta) }, ch07.ErrInvalidSignature},
+		{"D", func(ct *ch07.Ciphertext) { ct.D.Add(ct.D, pp.G2) }, ch07.ErrInvalidSignature},
+		{"E", func(ct *ch07.Ciphertext) { ct.E.Add(ct.E, pp.G2) }, ch07.ErrInvalidSignature},
+		{"S", func(ct *ch07.Ciphertext) { ct.S[0] ^= 1 }, ch07.ErrInvalidSignature},
+		{"C+resign", func(ct *ch07.Ciphertext) { ct.C.Mul(ct.C, delta); resign(ct) }, ch07.ErrInvalidCiphertext},
+	}
+
+	for _, m := range mauls {
+		t.Run(m.name, func(t *testing.T) {
+			ct := ch07.Encrypt(pp, alicePK, msg)
+			m.maul(ct)
+			if err := ct.Check(pp, alicePK); err != m.err {
+				t.Fatalf("expected %v from Check, but got %v", m.err, err)
+			}
+			if _, err := ch07.Decrypt(pp, aliceSK, ct); err != m.err {
+				t.Fatalf("expected %v from Decrypt, but got %v", m.err, err)
+			}
+			if err := ch07.ReEncrypt(pp, rk, bobPK, ct); err != m.err {
+				t.Fatalf("expected %v from ReEncrypt, but got %v", m.err, err)
+			}
+		})
+	}
+}
+
+// A ch07 ciphertext is bound to its recipient: neither a third party nor the
+// delegatee (without re-encryption) can decrypt it.
+func TestCH07_recipientBinding(t *testing.T) {
+	pp := ch07.NewPublicParams()
+	alicePK, _ := ch07.KeyGen(pp)
+	_, bobSK := ch07.KeyGen(pp)
+
+	ct := ch07.Encrypt(pp, alicePK, blspairing.NewRandomGt())
+	if _, err := ch07.Decrypt(pp, bobSK, ct); err != ch07.ErrInvalidCiphertext {
+		t.Fatalf("expected ErrInvalidCiphertext, but got %v", err)
+	}
+}
+
+// ch07 is bidirectional, so a re-encryption key is symmetric in the two
+// parties' secrets, and a proxy colluding with either party recovers the
+// other party's private key.  This documents an inherent weakness rather
+// than a property.
+func TestCH07_collusionRecoversDelegatorKey(t *testing.T) {
+	pp := ch07.NewPublicParams()
+	alicePK, aliceSK := ch07.KeyGen(pp)
+	_, bobSK := ch07.KeyGen(pp)
+	rk := ch07.ReEncryptionKeyGen(pp, aliceSK, bobSK)
+
+	// rk = b/a, so a = b/rk
+	x := new(bls.Scalar)
+	x.Inv(rk.RK)
+	x.Mul(x, bobSK.X)
+	recovered := &ch07.PrivateKey{X: x}
+
+	msg := blspairing.NewRandomGt()
+	got, err := ch07.Decrypt(pp, recovered, ch07.Encrypt(pp, alicePK, msg))
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if !got.IsEqual(msg) {
+		t.Fatal("collusion did not recover the delegator's key")
+	}
+}
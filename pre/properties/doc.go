@@ -0,0 +1,12 @@
+// Package properties holds no code of its own.  Its tests form a regression
+// suite that runs each proxy re-encryption scheme under pre/ through the
+// attacks that the scheme's documented properties should prevent: for
+// instance, re-encrypting an lv08 ciphertext twice, deriving a Bob-to-Alice
+// key from an afgh05 Alice-to-Bob key, or mauling a ch07 or lv08 ciphertext
+// so that its validity checks must fail.
+//
+// Some weaknesses are inherent to a scheme: in the bidirectional schemes
+// (bbs98, ch07) and in ga07, a proxy colluding with the delegatee recovers
+// the delegator's private key.  The suite checks that these weaknesses are
+// real, so that they are not mistaken for properties.
+package properties
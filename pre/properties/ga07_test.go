@@ -0,0 +1,59 @@
+package properties
+
+import (
+	"testing"
+
+	"github.com/etclab/ncircl/ibe/bf01"
+	"github.com/etclab/ncircl/pre/ga07"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// ga07 is unidirectional: an Alice-to-Bob key does not re-encrypt Bob's
+// ciphertexts for Alice.
+func TestGA07_unidirectional(t *testing.T) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	aliceSK := pkg.Extract([]byte("alice"))
+
+	rk := ga07.ReEncryptionKeyGen(pp, aliceSK, []byte("bob"))
+
+	msg := blspairing.NewRandomGt()
+	ct2 := ga07.ReEncrypt(pp, rk, ga07.Encrypt(pp, []byte("bob"), msg))
+	if ga07.Decrypt2(pp, aliceSK, ct2).IsEqual(msg) {
+		t.Fatal("an Alice-to-Bob key re-encrypted from Bob to Alice")
+	}
+}
+
+// A re-encryption key is specific to the delegatee identity.
+func TestGA07_delegateeBinding(t *testing.T) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	aliceSK := pkg.Extract([]byte("alice"))
+	carolSK := pkg.Extract([]byte("carol"))
+
+	rk := ga07.ReEncryptionKeyGen(pp, aliceSK, []byte("bob"))
+
+	msg := blspairing.NewRandomGt()
+	ct2 := ga07.ReEncrypt(pp, rk, ga07.Encrypt(pp, []byte("alice"), msg))
+	if ga07.Decrypt2(pp, carolSK, ct2).IsEqual(msg) {
+		t.Fatal("a non-delegatee decrypted a re-encrypted ciphertext")
+	}
+}
+
+// ga07 (IBP1) is not collusion-resistant, as its package documentation
+// states: the proxy and Bob together recover Alice's private key.
+func TestGA07_collusionRecoversDelegatorKey(t *testing.T) {
+	pkg, pp := bf01.NewPrivateKeyGenerator()
+	aliceSK := pkg.Extract([]byte("alice"))
+	bobSK := pkg.Extract([]byte("bob"))
+
+	rk := ga07.ReEncryptionKeyGen(pp, aliceSK, []byte("bob"))
+
+	// rk.RK = -sk + H2(X), and Bob decrypts X from rk.R
+	sk := ga07.H2(ga07.Decrypt1(pp, bobSK, rk.R))
+	neg := blspairing.CloneG1(rk.RK)
+	neg.Neg()
+	sk.Add(sk, neg)
+
+	if !sk.IsEqual(aliceSK.SK) {
+		t.Fatal("collusion did not recover the delegator's key")
+	}
+}
@@ -0,0 +1,150 @@
+package properties
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/mu"
+	"github.com/etclab/ncircl/pre/lv08"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+// lv08 is single-hop: a re-encrypted (first-level) ciphertext is a distinct
+// type, and recasting its components as a second-level ciphertext does not
+// get it past the next proxy's check.
+func TestLV08_singleHop(t *testing.T) {
+	pp := lv08.NewPublicParams()
+	alicePK, aliceSK := lv08.KeyGen(pp)
+	bobPK, bobSK := lv08.KeyGen(pp)
+	carolPK, _ := lv08.KeyGen(pp)
+
+	rkAliceToBob := lv08.ReEncryptionKeyGen(pp, aliceSK, bobPK)
+	rkBobToCarol := lv08.ReEncryptionKeyGen(pp, bobSK, carolPK)
+
+	msg := blspairing.NewRandomGt()
+	ct1, err := lv08.ReEncrypt(pp, rkAliceToBob, lv08.Encrypt2(pp, alicePK, msg))
+	if err != nil {
+		t.Fatalf("ReEncrypt failed: %v", err)
+	}
+
+	candidates := map[string]*bls.G2{
+		"C2Prime":       ct1.C2Prime,
+		"C2TriplePrime": ct1.C2TriplePrime,
+	}
+	for name, c2 := range candidates {
+		t.Run(name, func(t *testing.T) {
+			ct2 := &lv08.Ciphertext2{C1: ct1.C1, C2: c2, C3: ct1.C3, C4: ct1.C4, S: ct1.S}
+			if _, err := lv08.ReEncrypt(pp, rkBobToCarol, ct2); err != lv08.ErrInvalidCiphertext2 {
+				t.Fatalf("expected ErrInvalidCiphertext2, but got %v", err)
+			}
+		})
+	}
+}
+
+// lv08 is unidirectional: an Alice-to-Bob key does not re-encrypt Bob's
+// ciphertexts for Alice, even if the proxy lies about the delegator.
+func TestLV08_unidirectional(t *testing.T) {
+	pp := lv08.NewPublicParams()
+	alicePK, aliceSK := lv08.KeyGen(pp)
+	bobPK, _ := lv08.KeyGen(pp)
+	rk := lv08.ReEncryptionKeyGen(pp, aliceSK, bobPK)
+
+	msg := blspairing.NewRandomGt()
+	ct2 := lv08.Encrypt2(pp, bobPK, msg)
+	if _, err := lv08.ReEncrypt(pp, rk, ct2); err != lv08.ErrInvalidCiphertext2 {
+		t.Fatalf("expected ErrInvalidCiphertext2, but got %v", err)
+	}
+
+	spoofed := &lv08.ReEncryptionKey{DelegatorPK: bobPK, RK: rk.RK}
+	ct1, err := lv08.ReEncrypt(pp, spoofed, ct2)
+	if err != nil {
+		t.Fatalf("ReEncrypt failed: %v", err)
+	}
+	if err := ct1.Check(pp, alicePK); err != lv08.ErrInvalidCiphertext1 {
+		t.Fatalf("expected ErrInvalidCiphertext1, but got %v", err)
+	}
+	if _, err := lv08.Decrypt1(pp, aliceSK, ct1); err == nil {
+		t.Fatal("Decrypt1 accepted a ciphertext re-encrypted with a spoofed key")
+	}
+}
+
+// resignCiphertext2 replaces the one-time signature key of ct with a fresh
+// one, as an attacker who mauls a ciphertext would.
+func resignCiphertext2(ct *lv08.Ciphertext2) {
+	svk, ssk, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		mu.Panicf("ed25519.GenerateKey failed: %v", err)
+	}
+	ct.C1 = svk
+	ct.S = ed25519.Sign(ssk, ct.MessageToSign())
+}
+
+func TestLV08_maulCiphertext2(t *testing.T) {
+	pp := lv08.NewPublicParams()
+	alicePK, aliceSK := lv08.KeyGen(pp)
+	bobPK, _ := lv08.KeyGen(pp)
+	rk := lv08.ReEncryptionKeyGen(pp, aliceSK, bobPK)
+
+	msg := blspairing.NewRandomGt()
+	delta := blspairing.NewRandomGt()
+
+	mauls := []struct {
+		name string
+		maul func(ct *lv08.Ciphertext2)
+		err  error
+	}{
+		{"C2", func(ct *lv08.Ciphertext2) { ct.C2.Add(ct.C2, pp.G2) }, lv08.ErrInvalidCiphertext2},
+		{"C3", func(ct *lv08.Ciphertext2) { ct.C3.Mul(ct.C3, delta) }, lv08.ErrInvalidSignature},
+		{"C4", func(ct *lv08.Ciphertext2) { ct.C4.Add(ct.C4, pp.G1) }, lv08.ErrInvalidSignature},
+		{"S", func(ct *lv08.Ciphertext2) { ct.S[0] ^= 1 }, lv08.ErrInvalidSignature},
+		{"C3+resign", func(ct *lv08.Ciphertext2) { ct.C3.Mul(ct.C3, delta); resignCiphertext2(ct) }, lv08.ErrInvalidCiphertext2},
+	}
+
+	for _, m := range mauls {
+		t.Run(m.name, func(t *testing.T) {
+			ct := lv08.Encrypt2(pp, alicePK, msg)
+			m.maul(ct)
+			if err := ct.Check(pp, alicePK); err != m.err {
+				t.Fatalf("expected %v from Check, but got %v", m.err, err)
+			}
+			if _, err := lv08.Decrypt2(pp, aliceSK, ct); err != m.err {
+				t.Fatalf("expected %v from Decrypt2, but got %v", m.err, err)
+			}
+			if _, err := lv08.ReEncrypt(pp, rk, ct); err != m.err {
+				t.Fatalf("expected %v from ReEncrypt, but got %v", m.err, err)
+			}
+		})
+	}
+}
+
+func TestLV08_maulCiphertext1(t *testing.T) {
+	pp := lv08.NewPublicParams()
+	alicePK, aliceSK := lv08.KeyGen(pp)
+
+	msg := blspairing.NewRandomGt()
+	delta := blspairing.NewRandomGt()
+
+	mauls := []struct {
+		name string
+		maul func(ct *lv08.Ciphertext1)
+		err  error
+	}{
+		{"C2Prime", func(ct *lv08.Ciphertext1) { ct.C2Prime.Add(ct.C2Prime, pp.G2) }, lv08.ErrInvalidCiphertext1},
+		{"C2DoublePrime", func(ct *lv08.Ciphertext1) { ct.C2DoublePrime.Add(ct.C2DoublePrime, pp.G1) }, lv08.ErrInvalidCiphertext1},
+		{"C2TriplePrime", func(ct *lv08.Ciphertext1) { ct.C2TriplePrime.Add(ct.C2TriplePrime, pp.G2) }, lv08.ErrInvalidCiphertext1},
+		{"C3", func(ct *lv08.Ciphertext1) { ct.C3.Mul(ct.C3, delta) }, lv08.ErrInvalidSignature},
+		{"C4", func(ct *lv08.Ciphertext1) { ct.C4.Add(ct.C4, pp.G1) }, lv08.ErrInvalidSignature},
+	}
+
+	for _, m := range mauls {
+		t.Run(m.name, func(t *testing.T) {
+			ct := lv08.Encrypt1(pp, alicePK, msg)
+			m.maul(ct)
+			if _, err := lv08.Decrypt1(pp, aliceSK, ct); err != m.err {
+				t.Fatalf("expected %v, but got %v", m.err, err)
+			}
+		})
+	}
+}
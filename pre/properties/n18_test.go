@@ -0,0 +1,78 @@
+package properties
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/etclab/ncircl/pre/n18"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func TestN18_maulCiphertext(t *testing.T) {
+	pp := n18.NewPublicParams()
+	alicePK, aliceSK := n18.KeyGen(pp)
+	bobPK, _ := n18.KeyGen(pp)
+	kfrags, err := n18.ReEncryptionKeyGen(pp, aliceSK, bobPK, 1, 1)
+	if err != nil {
+		t.Fatalf("ReEncryptionKeyGen failed: %v", err)
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+
+	mauls := []struct {
+		name string
+		maul func(ct *n18.Ciphertext)
+		err  error
+	}{
+		{"E", func(ct *n18.Ciphertext) { ct.Capsule.E.Add(ct.Capsule.E, pp.G) }, n18.ErrInvalidCapsule},
+		{"V", func(ct *n18.Ciphertext) { ct.Capsule.V.Add(ct.Capsule.V, pp.G) }, n18.ErrInvalidCapsule},
+		{"S", func(ct *n18.Ciphertext) { ct.Capsule.S = blspairing.NewRandomScalar() }, n18.ErrInvalidCapsule},
+		{"Payload", func(ct *n18.Ciphertext) { ct.Payload[len(ct.Payload)-1] ^= 1 }, n18.ErrInvalidPayload},
+	}
+
+	for _, m := range mauls {
+		t.Run(m.name, func(t *testing.T) {
+			ct, err := n18.Encrypt(pp, alicePK, msg)
+			if err != nil {
+				t.Fatalf("Encrypt failed: %v", err)
+			}
+			m.maul(ct)
+			if _, err := n18.Decrypt(pp, aliceSK, ct); err != m.err {
+				t.Fatalf("expected %v from Decrypt, but got %v", m.err, err)
+			}
+			if m.err == n18.ErrInvalidCapsule {
+				if _, err := n18.ReEncrypt(pp, kfrags[0], ct.Capsule); err != m.err {
+					t.Fatalf("expected %v from ReEncrypt, but got %v", m.err, err)
+				}
+			}
+		})
+	}
+}
+
+// Capsule fragments for Bob are of no use to Carol.
+func TestN18_delegateeBinding(t *testing.T) {
+	pp := n18.NewPublicParams()
+	alicePK, aliceSK := n18.KeyGen(pp)
+	bobPK, _ := n18.KeyGen(pp)
+	_, carolSK := n18.KeyGen(pp)
+
+	kfrags, err := n18.ReEncryptionKeyGen(pp, aliceSK, bobPK, 1, 1)
+	if err != nil {
+		t.Fatalf("ReEncryptionKeyGen failed: %v", err)
+	}
+
+	msg := []byte("The quick brown fox jumps over the lazy dog.")
+	ct, err := n18.Encrypt(pp, alicePK, msg)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	cf, err := n18.ReEncrypt(pp, kfrags[0], ct.Capsule)
+	if err != nil {
+		t.Fatalf("ReEncrypt failed: %v", err)
+	}
+
+	got, err := n18.DecryptFragments(pp, carolSK, alicePK, ct, []*n18.CapsuleFragment{cf})
+	if err == nil || bytes.Equal(got, msg) {
+		t.Fatal("a non-delegatee decrypted with another delegatee's cfrags")
+	}
+}
@@ -0,0 +1,16 @@
+// Package proxy implements a re-encryption proxy service for the schemes in
+// [afgh05], [bbs98], [ch07] and [lv08].
+//
+// A [Registry] holds re-encryption keys indexed by (delegator, delegatee,
+// scheme).  Each key is wrapped in a [ReEncrypter], which binds it to its
+// scheme's public parameters and decodes and encodes that scheme's
+// ciphertexts with the scheme's own binary encoding (for instance,
+// [lv08.Ciphertext2.MarshalBinary]).  [NewHandler] exposes the registry over HTTP: a client POSTs a
+// serialized ciphertext and receives the re-encrypted ciphertext in the
+// response body.  Revoking a key (see [Registry.Revoke]) takes effect for all
+// subsequent requests.
+//
+// The handler does not authenticate clients, and key registration and
+// revocation are only available through the Go API; deployments are expected
+// to place the handler behind their own access control.
+package proxy
@@ -0,0 +1,257 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/etclab/ncircl/pre/afgh05"
+	"github.com/etclab/ncircl/pre/bbs98"
+	"github.com/etclab/ncircl/pre/ch07"
+	"github.com/etclab/ncircl/pre/lv08"
+)
+
+var (
+	ErrKeyNotFound       = errors.New("proxy: no re-encryption key for delegator, delegatee and scheme")
+	ErrInvalidCiphertext = errors.New("proxy: invalid ciphertext encoding")
+)
+
+func invalidCiphertext(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidCiphertext, err)
+}
+
+// MaxCiphertextSize bounds the size of a request body.
+const MaxCiphertextSize = 1 << 20
+
+type Scheme string
+
+const (
+	SchemeAFGH05 Scheme = "afgh05"
+	SchemeBBS98  Scheme = "bbs98"
+	SchemeCH07   Scheme = "ch07"
+	SchemeLV08   Scheme = "lv08"
+)
+
+// ReEncrypter re-encrypts serialized ciphertexts with a single re-encryption
+// key.
+type ReEncrypter interface {
+	Scheme() Scheme
+	ReEncrypt(ct []byte) ([]byte, error)
+}
+
+type afgh05ReEncrypter struct {
+	pp *afgh05.PublicParams
+	rk *afgh05.ReEncryptionKey
+}
+
+// NewAFGH05ReEncrypter takes first-level ciphertexts ([afgh05.Ciphertext1])
+// and returns second-level ciphertexts ([afgh05.Ciphertext2]).
+func NewAFGH05ReEncrypter(pp *afgh05.PublicParams, rk *afgh05.ReEncryptionKey) ReEncrypter {
+	return &afgh05ReEncrypter{pp: pp, rk: rk}
+}
+
+func (r *afgh05ReEncrypter) Scheme() Scheme { return SchemeAFGH05 }
+
+func (r *afgh05ReEncrypter) ReEncrypt(data []byte) ([]byte, error) {
+	ct1 := new(afgh05.Ciphertext1)
+	if err := ct1.UnmarshalBinary(data); err != nil {
+		return nil, invalidCiphertext(err)
+	}
+	return afgh05.ReEncrypt(r.pp, r.rk, ct1).MarshalBinary()
+}
+
+type bbs98ReEncrypter struct {
+	pp *bbs98.PublicParams
+	rk *bbs98.ReEncryptionKey
+}
+
+func NewBBS98ReEncrypter(pp *bbs98.PublicParams, rk *bbs98.ReEncryptionKey) ReEncrypter {
+	return &bbs98ReEncrypter{pp: pp, rk: rk}
+}
+
+func (r *bbs98ReEncrypter) Scheme() Scheme { return SchemeBBS98 }
+
+func (r *bbs98ReEncrypter) ReEncrypt(data []byte) ([]byte, error) {
+	ct, err := bbs98.UnmarshalCiphertext(r.pp, data)
+	if err != nil {
+		return nil, invalidCiphertext(err)
+	}
+	bbs98.ReEncrypt(r.pp, r.rk, ct)
+	return bbs98.MarshalCiphertext(r.pp, ct)
+}
+
+type ch07ReEncrypter struct {
+	pp    *ch07.PublicParams
+	rk    *ch07.ReEncryptionKey
+	bobPK *ch07.PublicKey
+}
+
+// NewCH07ReEncrypter needs the delegatee's public key, against which
+// [ch07.ReEncrypt] checks the re-encrypted ciphertext.
+func NewCH07ReEncrypter(pp *ch07.PublicParams, rk *ch07.ReEncryptionKey, bobPK *ch07.PublicKey) ReEncrypter {
+	return &ch07ReEncrypter{pp: pp, rk: rk, bobPK: bobPK}
+}
+
+func (r *ch07ReEncrypter) Scheme() Scheme { return SchemeCH07 }
+
+func (r *ch07ReEncrypter) ReEncrypt(data []byte) ([]byte, error) {
+	ct := new(ch07.Ciphertext)
+	if err := ct.UnmarshalBinary(data); err != nil {
+		return nil, invalidCiphertext(err)
+	}
+	if err := ch07.ReEncrypt(r.pp, r.rk, r.bobPK, ct); err != nil {
+		return nil, err
+	}
+	return ct.MarshalBinary()
+}
+
+type lv08ReEncrypter struct {
+	pp *lv08.PublicParams
+	rk *lv08.ReEncryptionKey
+}
+
+// NewLV08ReEncrypter takes second-level ciphertexts ([lv08.Ciphertext2]) and
+// returns first-level ciphertexts ([lv08.Ciphertext1]).
+func NewLV08ReEncrypter(pp *lv08.PublicParams, rk *lv08.ReEncryptionKey) ReEncrypter {
+	return &lv08ReEncrypter{pp: pp, rk: rk}
+}
+
+func (r *lv08ReEncrypter) Scheme() Scheme { return SchemeLV08 }
+
+func (r *lv08ReEncrypter) ReEncrypt(data []byte) ([]byte, error) {
+	ct2 := new(lv08.Ciphertext2)
+	if err := ct2.UnmarshalBinary(data); err != nil {
+		return nil, invalidCiphertext(err)
+	}
+	ct1, err := lv08.ReEncrypt(r.pp, r.rk, ct2)
+	if err != nil {
+		return nil, err
+	}
+	return ct1.MarshalBinary()
+}
+
+type RegistryKey struct {
+	Delegator string
+	Delegatee string
+	Scheme    Scheme
+}
+
+// Registry is a concurrency-safe store of re-encryption keys.
+type Registry struct {
+	mu   sync.RWMutex
+	keys map[RegistryKey]ReEncrypter
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		keys: make(map[RegistryKey]ReEncrypter),
+	}
+}
+
+// Register stores re under (delegator, delegatee, re.Scheme()), replacing any
+// previous key.
+func (r *Registry) Register(delegator, delegatee string, re ReEncrypter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[RegistryKey{Delegator: delegator, Delegatee: delegatee, Scheme: re.Scheme()}] = re
+}
+
+func (r *Registry) Lookup(delegator, delegatee string, scheme Scheme) (ReEncrypter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	re, ok := r.keys[RegistryKey{Delegator: delegator, Delegatee: delegatee, Scheme: scheme}]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return re, nil
+}
+
+// Revoke deletes the key for (delegator, delegatee, scheme).
+func (r *Registry) Revoke(delegator, delegatee string, scheme Scheme) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k := RegistryKey{Delegator: delegator, Delegatee: delegatee, Scheme: scheme}
+	if _, ok := r.keys[k]; !ok {
+		return ErrKeyNotFound
+	}
+	delete(r.keys, k)
+	return nil
+}
+
+// RevokeDelegator deletes all of a delegator's keys, and returns the number
+// of keys deleted.
+func (r *Registry) RevokeDelegator(delegator string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for k := range r.keys {
+		if k.Delegator == delegator {
+			delete(r.keys, k)
+			n++
+		}
+	}
+	return n
+}
+
+// Keys returns the index of every registered key, in no particular order.
+func (r *Registry) Keys() []RegistryKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]RegistryKey, 0, len(r.keys))
+	for k := range r.keys {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// NewHandler returns an HTTP handler for
+//
+//	POST /reencrypt?scheme=S&delegator=A&delegatee=B
+//
+// whose body is a ciphertext serialized in scheme S's wire format.  The
+// response is the re-encrypted ciphertext, with status 404 if there is no
+// (or no longer a) key for (A, B, S), status 413 if the body is larger than
+// [MaxCiphertextSize], and status 400 if the parameters are missing, the body
+// cannot be read, or the ciphertext does not decode or fails the scheme's
+// checks.
+func NewHandler(reg *Registry) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /reencrypt", func(w http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+		delegator := q.Get("delegator")
+		delegatee := q.Get("delegatee")
+		scheme := Scheme(q.Get("scheme"))
+		if delegator == "" || delegatee == "" || scheme == "" {
+			http.Error(w, "missing scheme, delegator or delegatee", http.StatusBadRequest)
+			return
+		}
+
+		re, err := reg.Lookup(delegator, delegatee, scheme)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		ct, err := io.ReadAll(http.MaxBytesReader(w, req.Body, MaxCiphertextSize))
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.As(err, new(*http.MaxBytesError)) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		out, err := re.ReEncrypt(ct)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(out)
+	})
+	return mux
+}
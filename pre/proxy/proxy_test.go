@@ -0,0 +1,262 @@
+package proxy
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"testing/iotest"
+
+	"github.com/etclab/ncircl/ecc"
+	"github.com/etclab/ncircl/pre/afgh05"
+	"github.com/etclab/ncircl/pre/bbs98"
+	"github.com/etclab/ncircl/pre/ch07"
+	"github.com/etclab/ncircl/pre/lv08"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+func post(t *testing.T, srv *httptest.Server, scheme Scheme, delegator, delegatee string, body []byte) (int, []byte) {
+	t.Helper()
+	q := url.Values{}
+	q.Set("scheme", string(scheme))
+	q.Set("delegator", delegator)
+	q.Set("delegatee", delegatee)
+	resp, err := http.Post(srv.URL+"/reencrypt?"+q.Encode(), "application/octet-stream", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("http.Post failed: %v", err)
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("io.ReadAll failed: %v", err)
+	}
+	return resp.StatusCode, out
+}
+
+func marshal(t *testing.T, v encoding.BinaryMarshaler) []byte {
+	t.Helper()
+	data, err := v.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	return data
+}
+
+func TestHandler(t *testing.T) {
+	reg := NewRegistry()
+	srv := httptest.NewServer(NewHandler(reg))
+	defer srv.Close()
+
+	t.Run("afgh05", func(t *testing.T) {
+		pp := afgh05.NewPublicParams()
+		alicePK, aliceSK := afgh05.KeyGen(pp)
+		bobPK, bobSK := afgh05.KeyGen(pp)
+		reg.Register("alice", "bob", NewAFGH05ReEncrypter(pp, afgh05.ReEncryptionKeyGen(pp, aliceSK, bobPK)))
+
+		msg := blspairing.NewRandomGt()
+		ct1 := afgh05.Encrypt(pp, alicePK, msg)
+		status, body := post(t, srv, SchemeAFGH05, "alice", "bob", marshal(t, ct1))
+		if status != http.StatusOK {
+			t.Fatalf("expected status 200, but got %d: %s", status, body)
+		}
+		ct2 := new(afgh05.Ciphertext2)
+		if err := ct2.UnmarshalBinary(body); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+		if !afgh05.Decrypt2(pp, bobSK, ct2).IsEqual(msg) {
+			t.Fatal("Decrypt2 did not produce the original message")
+		}
+	})
+
+	t.Run("bbs98", func(t *testing.T) {
+		pp := bbs98.NewPublicParams(elliptic.P256())
+		alicePK, aliceSK := bbs98.KeyGen(pp)
+		_, bobSK := bbs98.KeyGen(pp)
+		reg.Register("alice", "bob", NewBBS98ReEncrypter(pp, bbs98.ReEncryptionKeyGen(pp, aliceSK, bobSK)))
+
+		msg := ecc.NewRandomPoint(pp.Curve)
+		ct, err := bbs98.Encrypt(pp, alicePK, msg)
+		if err != nil {
+			t.Fatalf("Encrypt failed: %v", err)
+		}
+		data, err := bbs98.MarshalCiphertext(pp, ct)
+		if err != nil {
+			t.Fatalf("MarshalCiphertext failed: %v", err)
+		}
+		status, body := post(t, srv, SchemeBBS98, "alice", "bob", data)
+		if status != http.StatusOK {
+			t.Fatalf("expected status 200, but got %d: %s", status, body)
+		}
+		ct, err = bbs98.UnmarshalCiphertext(pp, body)
+		if err != nil {
+			t.Fatalf("UnmarshalCiphertext failed: %v", err)
+		}
+		if !bbs98.Decrypt(pp, bobSK, ct).Equal(msg) {
+			t.Fatal("Decrypt did not produce the original message")
+		}
+	})
+
+	t.Run("ch07", func(t *testing.T) {
+		pp := ch07.NewPublicParams()
+		alicePK, aliceSK := ch07.KeyGen(pp)
+		bobPK, bobSK := ch07.KeyGen(pp)
+		reg.Register("alice", "bob", NewCH07ReEncrypter(pp, ch07.ReEncryptionKeyGen(pp, aliceSK, bobSK), bobPK))
+
+		msg := blspairing.NewRandomGt()
+		ct := ch07.Encrypt(pp, alicePK, msg)
+		status, body := post(t, srv, SchemeCH07, "alice", "bob", marshal(t, ct))
+		if status != http.StatusOK {
+			t.Fatalf("expected status 200, but got %d: %s", status, body)
+		}
+		ct = new(ch07.Ciphertext)
+		if err := ct.UnmarshalBinary(body); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+		got, err := ch07.Decrypt(pp, bobSK, ct)
+		if err != nil {
+			t.Fatalf("Decrypt failed: %v", err)
+		}
+		if !got.IsEqual(msg) {
+			t.Fatal("Decrypt did not produce the original message")
+		}
+	})
+
+	t.Run("lv08", func(t *testing.T) {
+		pp := lv08.NewPublicParams()
+		alicePK, aliceSK := lv08.KeyGen(pp)
+		bobPK, bobSK := lv08.KeyGen(pp)
+		reg.Register("alice", "bob", NewLV08ReEncrypter(pp, lv08.ReEncryptionKeyGen(pp, aliceSK, bobPK)))
+
+		msg := blspairing.NewRandomGt()
+		ct2 := lv08.Encrypt2(pp, alicePK, msg)
+		status, body := post(t, srv, SchemeLV08, "alice", "bob", marshal(t, ct2))
+		if status != http.StatusOK {
+			t.Fatalf("expected status 200, but got %d: %s", status, body)
+		}
+		ct1 := new(lv08.Ciphertext1)
+		if err := ct1.UnmarshalBinary(body); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+		got, err := lv08.Decrypt1(pp, bobSK, ct1)
+		if err != nil {
+			t.Fatalf("Decrypt1 failed: %v", err)
+		}
+		if !got.IsEqual(msg) {
+			t.Fatal("Decrypt1 did not produce the original message")
+		}
+	})
+
+	if n := len(reg.Keys()); n != 4 {
+		t.Fatalf("expected 4 registered keys, but got %d", n)
+	}
+}
+
+func TestHandler_revocation(t *testing.T) {
+	reg := NewRegistry()
+	srv := httptest.NewServer(NewHandler(reg))
+	defer srv.Close()
+
+	pp := afgh05.NewPublicParams()
+	alicePK, aliceSK := afgh05.KeyGen(pp)
+	bobPK, _ := afgh05.KeyGen(pp)
+	carolPK, _ := afgh05.KeyGen(pp)
+	reg.Register("alice", "bob", NewAFGH05ReEncrypter(pp, afgh05.ReEncryptionKeyGen(pp, aliceSK, bobPK)))
+	reg.Register("alice", "carol", NewAFGH05ReEncrypter(pp, afgh05.ReEncryptionKeyGen(pp, aliceSK, carolPK)))
+
+	ct := marshal(t, afgh05.Encrypt(pp, alicePK, blspairing.NewRandomGt()))
+
+	if status, body := post(t, srv, SchemeAFGH05, "alice", "bob", ct); status != http.StatusOK {
+		t.Fatalf("expected status 200, but got %d: %s", status, body)
+	}
+
+	if err := reg.Revoke("alice", "bob", SchemeAFGH05); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if err := reg.Revoke("alice", "bob", SchemeAFGH05); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound, but got %v", err)
+	}
+	if status, _ := post(t, srv, SchemeAFGH05, "alice", "bob", ct); status != http.StatusNotFound {
+		t.Fatalf("expected status 404 after revocation, but got %d", status)
+	}
+	if status, body := post(t, srv, SchemeAFGH05, "alice", "carol", ct); status != http.StatusOK {
+		t.Fatalf("expected status 200 for an unrevoked key, but got %d: %s", status, body)
+	}
+
+	if n := reg.RevokeDelegator("alice"); n != 1 {
+		t.Fatalf("expected RevokeDelegator to delete 1 key, but it deleted %d", n)
+	}
+	if status, _ := post(t, srv, SchemeAFGH05, "alice", "carol", ct); status != http.StatusNotFound {
+		t.Fatalf("expected status 404 after revocation, but got %d", status)
+	}
+}
+
+func TestHandler_badRequests(t *testing.T) {
+	reg := NewRegistry()
+	srv := httptest.NewServer(NewHandler(reg))
+	defer srv.Close()
+
+	pp := lv08.NewPublicParams()
+	alicePK, aliceSK := lv08.KeyGen(pp)
+	bobPK, _ := lv08.KeyGen(pp)
+	reg.Register("alice", "bob", NewLV08ReEncrypter(pp, lv08.ReEncryptionKeyGen(pp, aliceSK, bobPK)))
+
+	ct2 := marshal(t, lv08.Encrypt2(pp, alicePK, blspairing.NewRandomGt()))
+
+	trials := []struct {
+		name   string
+		scheme Scheme
+		body   []byte
+		status int
+	}{
+		{"wrong scheme", SchemeAFGH05, ct2, http.StatusNotFound},
+		{"truncated", SchemeLV08, ct2[:len(ct2)-1], http.StatusBadRequest},
+		{"trailing data", SchemeLV08, append(bytes.Clone(ct2), 0), http.StatusBadRequest},
+		{"bad signature", SchemeLV08, func() []byte {
+			b := bytes.Clone(ct2)
+			b[len(b)-1] ^= 1
+			return b
+		}(), http.StatusBadRequest},
+	}
+
+	for _, trial := range trials {
+		t.Run(trial.name, func(t *testing.T) {
+			status, body := post(t, srv, trial.scheme, "alice", "bob", trial.body)
+			if status != trial.status {
+				t.Fatalf("expected status %d, but got %d: %s", trial.status, status, body)
+			}
+		})
+	}
+
+	resp, err := http.Get(srv.URL + "/reencrypt?" + fmt.Sprintf("scheme=%s&delegator=alice&delegatee=bob", SchemeLV08))
+	if err != nil {
+		t.Fatalf("http.Get failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status 405 for GET, but got %d", resp.StatusCode)
+	}
+
+	status, _ := post(t, srv, SchemeLV08, "", "bob", ct2)
+	if status != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for a missing delegator, but got %d", status)
+	}
+
+	status, _ = post(t, srv, SchemeLV08, "alice", "bob", make([]byte, MaxCiphertextSize+1))
+	if status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status 413 for an oversized body, but got %d", status)
+	}
+
+	// a body that fails to read for another reason than its size
+	target := "/reencrypt?" + fmt.Sprintf("scheme=%s&delegator=alice&delegatee=bob", SchemeLV08)
+	req := httptest.NewRequest(http.MethodPost, target, iotest.ErrReader(io.ErrUnexpectedEOF))
+	rec := httptest.NewRecorder()
+	NewHandler(reg).ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for an unreadable body, but got %d", rec.Code)
+	}
+}
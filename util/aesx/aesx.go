@@ -5,11 +5,14 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"errors"
+
+	"github.com/etclab/ncircl/util/bytesx"
 )
 
 var (
 	ErrInputNotBlockSized = errors.New("aesx: input buffer is not a multiple of the block size")
 	ErrOutputBufSize      = errors.New("aesx: output buffer does not equal input buffer size")
+	ErrGCMOpen            = errors.New("aesx: GCM authentication failed")
 )
 
 type ECB struct {
@@ -111,3 +114,51 @@ func EncryptCTR(key, iv, data []byte) ([]byte, error) {
 func DecryptCTR(key, iv, data []byte) ([]byte, error) {
 	return DoCTR(key, iv, data)
 }
+
+// NewGCM creates a [cipher.AEAD] for AES GCM mode with the standard nonce
+// size.
+func NewGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptGCM performs a one-shot AES GCM encryption of the plaintext data,
+// authenticating additionalData along with it.  The function generates a
+// random nonce and returns nonce || ciphertext || tag.  Unlike the CTR
+// functions, the input slice is not modified.
+func EncryptGCM(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := NewGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := bytesx.Random(aead.NonceSize())
+	out := make([]byte, len(nonce), len(nonce)+len(plaintext)+aead.Overhead())
+	copy(out, nonce)
+	return aead.Seal(out, nonce, plaintext, additionalData), nil
+}
+
+// DecryptGCM is the inverse of [EncryptGCM]: it splits the nonce from the
+// data, and decrypts and authenticates the remainder.  The function returns
+// [ErrGCMOpen] if the data is too short or fails authentication.
+func DecryptGCM(key, data, additionalData []byte) ([]byte, error) {
+	aead, err := NewGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrGCMOpen
+	}
+
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrGCMOpen
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,225 @@
+// Package binaryx provides a small encoder and decoder for the fixed-layout
+// binary encodings of keys and ciphertexts.
+//
+// Each encoding starts with a two-byte header: a format version and a kind
+// that identifies the encoded type.  Pairing-group elements are written in
+// compressed form, and decoding checks that each element is in the
+// prime-order subgroup.  Decoding is strict: a wrong header, a short buffer,
+// an invalid element, or trailing bytes are all errors.
+package binaryx
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	bls "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/etclab/ncircl/util/blspairing"
+)
+
+var (
+	ErrInvalidHeader  = errors.New("binaryx: invalid header")
+	ErrTruncated      = errors.New("binaryx: truncated data")
+	ErrTrailingData   = errors.New("binaryx: trailing data")
+	ErrInvalidElement = errors.New("binaryx: invalid element")
+)
+
+const HeaderSize = 2
+
+type Encoder struct {
+	buf []byte
+}
+
+func NewEncoder(version, kind byte) *Encoder {
+	return &Encoder{buf: []byte{version, kind}}
+}
+
+// Bytes returns the encoding so far.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+func (e *Encoder) PutByte(b byte) {
+	e.buf = append(e.buf, b)
+}
+
+func (e *Encoder) PutBytes(b []byte) {
+	e.buf = append(e.buf, b...)
+}
+
+func (e *Encoder) PutG1(g *bls.G1) {
+	e.buf = append(e.buf, g.BytesCompressed()...)
+}
+
+func (e *Encoder) PutG2(g *bls.G2) {
+	e.buf = append(e.buf, g.BytesCompressed()...)
+}
+
+func (e *Encoder) PutGt(g *bls.Gt) {
+	e.buf = append(e.buf, blspairing.GtToBytes(g)...)
+}
+
+func (e *Encoder) PutScalar(s *bls.Scalar) {
+	e.buf = append(e.buf, blspairing.ScalarToBytes(s)...)
+}
+
+// PutPoint writes the compressed form of the point (x, y) on curve.
+func (e *Encoder) PutPoint(curve elliptic.Curve, x, y *big.Int) {
+	e.buf = append(e.buf, elliptic.MarshalCompressed(curve, x, y)...)
+}
+
+// PutBigInt writes x as a big-endian integer of exactly size bytes.
+func (e *Encoder) PutBigInt(x *big.Int, size int) {
+	b := make([]byte, size)
+	x.FillBytes(b)
+	e.buf = append(e.buf, b...)
+}
+
+// Decoder reads the fields of an encoding in order.  The first error is
+// sticky: once a read fails, later reads return zero values, and [Decoder.Finish]
+// reports the error.
+type Decoder struct {
+	data []byte
+	err  error
+}
+
+// NewDecoder checks that data starts with the header for the given version
+// and kind.
+func NewDecoder(data []byte, version, kind byte) *Decoder {
+	d := &Decoder{data: data}
+	h := d.next(HeaderSize)
+	if d.err == nil && (h[0] != version || h[1] != kind) {
+		d.err = ErrInvalidHeader
+	}
+	return d
+}
+
+func (d *Decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.data) < n {
+		d.err = ErrTruncated
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *Decoder) Byte() byte {
+	b := d.next(1)
+	if d.err != nil {
+		return 0
+	}
+	return b[0]
+}
+
+// Bytes returns a copy of the next n bytes.
+func (d *Decoder) Bytes(n int) []byte {
+	b := d.next(n)
+	if d.err != nil {
+		return nil
+	}
+	out := make([]byte, n)
+	copy(out, b)
+	return out
+}
+
+func (d *Decoder) G1() *bls.G1 {
+	g := new(bls.G1)
+	b := d.next(bls.G1SizeCompressed)
+	if d.err == nil && g.SetBytes(b) != nil {
+		d.err = ErrInvalidElement
+	}
+	return g
+}
+
+func (d *Decoder) G2() *bls.G2 {
+	g := new(bls.G2)
+	b := d.next(bls.G2SizeCompressed)
+	if d.err == nil && g.SetBytes(b) != nil {
+		d.err = ErrInvalidElement
+	}
+	return g
+}
+
+// Gt also checks that the element has order r; circl's UnmarshalBinary
+// accepts any element of the extension field.
+func (d *Decoder) Gt() *bls.Gt {
+	g := new(bls.Gt)
+	b := d.next(bls.GtSize)
+	if d.err != nil {
+		return g
+	}
+	if g.UnmarshalBinary(b) != nil || !isInGt(g) {
+		d.err = ErrInvalidElement
+	}
+	return g
+}
+
+// isInGt checks that g^r = 1, computed as g^(r-1) * g.
+func isInGt(g *bls.Gt) bool {
+	rMinusOne := blspairing.NewScalarOne()
+	rMinusOne.Neg()
+	x := new(bls.Gt)
+	x.Exp(g, rMinusOne)
+	x.Mul(x, g)
+	return x.IsIdentity()
+}
+
+// Scalar rejects encodings that are not fully reduced modulo r.
+func (d *Decoder) Scalar() *bls.Scalar {
+	s := new(bls.Scalar)
+	b := d.next(bls.ScalarSize)
+	if d.err == nil && s.UnmarshalBinary(b) != nil {
+		d.err = ErrInvalidElement
+	}
+	return s
+}
+
+// NonZeroScalar is like [Decoder.Scalar], but also rejects zero, as for
+// private keys and other scalars that are inverted.
+func (d *Decoder) NonZeroScalar() *bls.Scalar {
+	s := d.Scalar()
+	if d.err == nil && s.IsZero() == 1 {
+		d.err = ErrInvalidElement
+	}
+	return s
+}
+
+// Point reads a compressed point and checks that it is on curve.
+func (d *Decoder) Point(curve elliptic.Curve) (*big.Int, *big.Int) {
+	b := d.next(1 + (curve.Params().BitSize+7)/8)
+	if d.err != nil {
+		return nil, nil
+	}
+	x, y := elliptic.UnmarshalCompressed(curve, b)
+	if x == nil {
+		d.err = ErrInvalidElement
+	}
+	return x, y
+}
+
+// BigInt reads a big-endian integer of exactly size bytes and checks that
+// it is in [1, max).
+func (d *Decoder) BigInt(size int, max *big.Int) *big.Int {
+	b := d.next(size)
+	if d.err != nil {
+		return nil
+	}
+	x := new(big.Int).SetBytes(b)
+	if x.Sign() <= 0 || x.Cmp(max) >= 0 {
+		d.err = ErrInvalidElement
+	}
+	return x
+}
+
+// Finish returns the first error encountered, or [ErrTrailingData] if any
+// bytes remain unread.
+func (d *Decoder) Finish() error {
+	if d.err == nil && len(d.data) != 0 {
+		d.err = ErrTrailingData
+	}
+	return d.err
+}